@@ -64,6 +64,21 @@ func New(configPath string, fn GameBootstrapV2) (*App, error) {
 
 	engine.PutInContainer(boot)
 
+	simService := app.ctn.Get(constants.SimulatorServiceName).(*services.SimulatorService)
+	simService.WithBootstrapFactory(func(rtp, volatilityType string) (*engine.Bootstrap, error) {
+		rtpValue, err := strconv.ParseFloat(rtp, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing rtp: %w", err)
+		}
+
+		vol, err := volatility.VolFromStr(volatilityType)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing volatility: %w", err)
+		}
+
+		return fn(rand, vol, rtpValue), nil
+	})
+
 	return app, nil
 }
 
@@ -83,6 +83,22 @@ func (c *Client) AtomicBet(ctx context.Context, sessionToken, freeBetID, roundID
 
 }
 
+func (c *Client) RollbackBet(ctx context.Context, sessionToken, transactionID string) (*overlord.RollbackOut, error) {
+	req := &RollbackReq{SessionToken: sessionToken, TransactionID: transactionID}
+	resp := &RollbackBetResp{}
+
+	if err := askServer(ctx, buildURL(c.cfg.BaseURL, RollbackBetPath), req, resp)(c.cfg.WithLogs); err != nil {
+		return nil, err
+	}
+
+	balance, err := cryptolutToEjawBalance(resp.Balance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &overlord.RollbackOut{Balance: balance}, nil
+}
+
 func (c *Client) GetAvailableFreeSpins(ctx context.Context, sessionToken string) (*overlord.GetAvailableFreeBetsOut, error) {
 	return &overlord.GetAvailableFreeBetsOut{}, nil
 }
@@ -0,0 +1,62 @@
+package jackpot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryStorage struct {
+	pools map[Key]*Pool
+	mu    sync.Mutex
+}
+
+// NewMemoryStorage returns the storage for a single server, pools are lost on restart.
+func NewMemoryStorage() Storage {
+	return &memoryStorage{pools: map[Key]*Pool{}}
+}
+
+func (m *memoryStorage) Get(_ context.Context, key Key, seed int64) (*Pool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool := *m.pool(key, seed)
+
+	return &pool, nil
+}
+
+func (m *memoryStorage) Contribute(_ context.Context, key Key, seed, amount int64) (*Pool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool := m.pool(key, seed)
+	pool.Contributions += amount
+	pool.UpdatedAt = time.Now()
+
+	cp := *pool
+
+	return &cp, nil
+}
+
+func (m *memoryStorage) Take(_ context.Context, key Key, seed int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool := m.pool(key, seed)
+	value := pool.Value()
+
+	pool.Contributions = 0
+	pool.UpdatedAt = time.Now()
+
+	return value, nil
+}
+
+func (m *memoryStorage) pool(key Key, seed int64) *Pool {
+	pool, ok := m.pools[key]
+	if !ok {
+		pool = &Pool{Key: key, Seed: seed, UpdatedAt: time.Now()}
+		m.pools[key] = pool
+	}
+
+	return pool
+}
@@ -0,0 +1,42 @@
+package jackpot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage()
+	key := Key{Game: "game", Currency: "usd", Tier: "grand"}
+
+	pool, err := storage.Get(ctx, key, 1000)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), pool.Value())
+
+	pool, err = storage.Contribute(ctx, key, 1000, 50)
+	require.NoError(t, err)
+	require.Equal(t, int64(1050), pool.Value())
+
+	// the returned pool is a copy
+	pool.Contributions = 0
+
+	pool, err = storage.Contribute(ctx, key, 1000, -20)
+	require.NoError(t, err)
+	require.Equal(t, int64(1030), pool.Value())
+
+	value, err := storage.Take(ctx, key, 1000)
+	require.NoError(t, err)
+	require.Equal(t, int64(1030), value)
+
+	pool, err = storage.Get(ctx, key, 1000)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), pool.Value())
+
+	// the pools are separated by the currency
+	pool, err = storage.Get(ctx, Key{Game: "game", Currency: "eur", Tier: "grand"}, 500)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), pool.Value())
+}
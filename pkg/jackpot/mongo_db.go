@@ -0,0 +1,106 @@
+package jackpot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const PoolsCollectionName = "jackpot_pools"
+
+type mongoDBStorage struct {
+	coll   *mongo.Collection
+	client *mongo.Client
+}
+
+type MongoDBConfig struct {
+	URL  string
+	Name string
+}
+
+func NewMongoDBStorage(cfg *MongoDBConfig) (Storage, error) {
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(cfg.URL))
+	if err != nil {
+		zap.S().Error(err)
+		return nil, err
+	}
+
+	if err = client.Ping(context.TODO(), nil); err != nil {
+		return nil, err
+	}
+
+	coll := client.Database(cfg.Name).Collection(PoolsCollectionName)
+
+	_, err = coll.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "game", Value: 1}, {Key: "currency", Value: 1}, {Key: "tier", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoDBStorage{coll: coll, client: client}, nil
+}
+
+func (m *mongoDBStorage) Get(ctx context.Context, key Key, seed int64) (*Pool, error) {
+	var pool Pool
+
+	err := m.coll.FindOne(ctx, filter(key)).Decode(&pool)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &Pool{Key: key, Seed: seed}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool, nil
+}
+
+func (m *mongoDBStorage) Contribute(ctx context.Context, key Key, seed, amount int64) (*Pool, error) {
+	var pool Pool
+
+	err := m.coll.FindOneAndUpdate(ctx, filter(key),
+		bson.D{
+			{Key: "$inc", Value: bson.D{{Key: "contributions", Value: amount}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+			{Key: "$setOnInsert", Value: bson.D{{Key: "seed", Value: seed}}},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&pool)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool, nil
+}
+
+func (m *mongoDBStorage) Take(ctx context.Context, key Key, seed int64) (int64, error) {
+	var pool Pool
+
+	err := m.coll.FindOneAndUpdate(ctx, filter(key),
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "contributions", Value: 0}, {Key: "updated_at", Value: time.Now()}}},
+			{Key: "$setOnInsert", Value: bson.D{{Key: "seed", Value: seed}}},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)).Decode(&pool)
+
+	// the pool is just created with the seed
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return seed, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return pool.Value(), nil
+}
+
+func filter(key Key) bson.D {
+	return bson.D{{Key: "game", Value: key.Game}, {Key: "currency", Value: key.Currency}, {Key: "tier", Value: key.Tier}}
+}
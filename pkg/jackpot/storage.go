@@ -0,0 +1,35 @@
+package jackpot
+
+import (
+	"context"
+	"time"
+)
+
+// Key identifies the progressive pool.
+type Key struct {
+	Game     string `bson:"game"`
+	Currency string `bson:"currency"`
+	Tier     string `bson:"tier"`
+}
+
+type Pool struct {
+	Key           `bson:",inline"`
+	Seed          int64     `bson:"seed"`
+	Contributions int64     `bson:"contributions"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
+
+// Value is the amount the pool pays out.
+func (p *Pool) Value() int64 {
+	return p.Seed + p.Contributions
+}
+
+// Storage keeps progressive pools. Missing pools are created with the seed on the first access,
+// so the implementations must be safe for concurrent use by several servers of the game.
+type Storage interface {
+	Get(ctx context.Context, key Key, seed int64) (*Pool, error)
+	// Contribute adds the amount (can be negative on rollback) to the pool and returns the updated pool.
+	Contribute(ctx context.Context, key Key, seed, amount int64) (*Pool, error)
+	// Take returns the value of the pool and resets it to the seed.
+	Take(ctx context.Context, key Key, seed int64) (int64, error)
+}
@@ -6,6 +6,7 @@ import (
 
 	"bitbucket.org/play-workspace/base-slot-server/pkg/cryptolut_rgs"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/jackpot"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/constants"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/services"
@@ -13,6 +14,7 @@ import (
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/transport/websocket"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/overlord"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rounds"
 	"bitbucket.org/play-workspace/gocommon/tracer"
 	"github.com/spf13/viper"
 )
@@ -28,12 +30,23 @@ type Config struct {
 	CryptolutConfig      *cryptolut_rgs.Config
 	HistoryConfig        *history.Config
 	HistoryMongoDBConfig *history.MongoDBConfig
+	JackpotMongoDBConfig *jackpot.MongoDBConfig
+	RoundsMongoDBConfig  *rounds.MongoDBConfig
 	RNGConfig            *rng.Config
 	TracerConfig         *tracer.Config
 
 	ConstantsConfig *constants.Config
 	EngineConfig    *engine.Config
 	SimulatorConfig *services.SimulatorConfig
+	// SimulatorJobsConfig is optional, simulation jobs are kept in memory without it.
+	SimulatorJobsConfig *services.SimulatorJobsConfig
+	// ResponsibleGamingConfig is optional, no limits are applied without it.
+	// The sessions are kept in memory, so the limits are per server and reset on restart.
+	ResponsibleGamingConfig *services.ResponsibleGamingConfig
+	// GameStateCacheConfig is optional, the game states are not cached without it.
+	GameStateCacheConfig *services.GameStateCacheConfig
+	// IdempotencyConfig is optional, the results of the wagers are kept for the default ttl without it.
+	IdempotencyConfig *services.IdempotencyConfig
 }
 
 func New(path string) (*Config, error) {
@@ -63,11 +76,17 @@ func build() (*Config, error) {
 	cryptolutConfig := viper.Sub("cryptolut")
 	historyConfig := viper.Sub("history")
 	historyMongoDBConfig := viper.Sub("historyMongoDB")
+	jackpotMongoDBConfig := viper.Sub("jackpotMongoDB")
+	roundsMongoDBConfig := viper.Sub("roundsMongoDB")
 	constantsConfig := viper.Sub("game")
 	rngConfig := viper.Sub("rng")
 	engineConfig := viper.Sub("engine")
 	tracerConfig := viper.Sub("tracer")
 	simulatorConfig := viper.Sub("simulator")
+	simulatorJobsConfig := viper.Sub("simulatorJobs")
+	responsibleGamingConfig := viper.Sub("responsibleGaming")
+	gameStateCacheConfig := viper.Sub("gameStateCache")
+	idempotencyConfig := viper.Sub("idempotency")
 
 	if err := parseSubConfig(serverConfig, &config.ServerConfig); err != nil {
 		return nil, err
@@ -93,6 +112,14 @@ func build() (*Config, error) {
 		return nil, err
 	}
 
+	if err := parseSubConfigIfNotNil(jackpotMongoDBConfig, &config.JackpotMongoDBConfig); err != nil {
+		return nil, err
+	}
+
+	if err := parseSubConfigIfNotNil(roundsMongoDBConfig, &config.RoundsMongoDBConfig); err != nil {
+		return nil, err
+	}
+
 	if err := parseSubConfig(rngConfig, &config.RNGConfig); err != nil {
 		return nil, err
 	}
@@ -109,6 +136,22 @@ func build() (*Config, error) {
 		return nil, err
 	}
 
+	if err := parseSubConfigIfNotNil(simulatorJobsConfig, &config.SimulatorJobsConfig); err != nil {
+		return nil, err
+	}
+
+	if err := parseSubConfigIfNotNil(responsibleGamingConfig, &config.ResponsibleGamingConfig); err != nil {
+		return nil, err
+	}
+
+	if err := parseSubConfigIfNotNil(gameStateCacheConfig, &config.GameStateCacheConfig); err != nil {
+		return nil, err
+	}
+
+	if err := parseSubConfigIfNotNil(idempotencyConfig, &config.IdempotencyConfig); err != nil {
+		return nil, err
+	}
+
 	if tracerConfig != nil {
 		if err := tracerConfig.Unmarshal(&config.TracerConfig); err != nil {
 			panic(err)
@@ -14,6 +14,8 @@ const (
 	WebsocketServerName = "WebsocketServer"
 	ValidatorName       = "Validator"
 	TracerName          = "Tracer"
+	JackpotName         = "Jackpot"
+	RoundsName          = "Rounds"
 
 	HTTPGameFlowHandlerName  = "HTTPGameFlowHandler"
 	HTTPCheatsHandlerName    = "HTTPCheatsHandler"
@@ -30,9 +32,14 @@ const (
 	WSGameFlowHandlerName = "WSGameFlowHandlerName"
 	WSCheatsHandlerName   = "WSCheatsHandlerName"
 
-	GameFlowServiceName  = "GameFlowService"
-	HistoryServiceName   = "HistoryService"
-	SimulatorServiceName = "SimulatorService"
-	FreeSpinServiceName  = "FreeSpinService"
-	CheatsServiceName    = "CheatsService"
+	GameFlowServiceName    = "GameFlowService"
+	HistoryServiceName     = "HistoryService"
+	SimulatorServiceName   = "SimulatorService"
+	FreeSpinServiceName    = "FreeSpinService"
+	CheatsServiceName      = "CheatsService"
+	JackpotServiceName     = "JackpotService"
+	IdempotencyServiceName = "IdempotencyService"
+	RoundServiceName       = "RoundService"
+	GamingServiceName      = "ResponsibleGamingService"
+	GameStateCacheName     = "GameStateCache"
 )
@@ -116,8 +116,12 @@ func buildGenerals(configPath string) []di.Def {
 				history := ctn.Get(constants.HistoryServiceName).(*services.HistoryService)
 				freeSpin := ctn.Get(constants.FreeSpinServiceName).(*services.FreeSpinService)
 				cheats := ctn.Get(constants.CheatsServiceName).(*services.CheatsService)
+				idempotency := ctn.Get(constants.IdempotencyServiceName).(*services.IdempotencyService)
+				round := ctn.Get(constants.RoundServiceName).(*services.RoundService)
+				gaming := ctn.Get(constants.GamingServiceName).(*services.ResponsibleGamingService)
+				stateCache := ctn.Get(constants.GameStateCacheName).(services.GameStateCache)
 
-				return facade.NewFacade(validationEngine, gameFlow, history, freeSpin, cheats), nil
+				return facade.NewFacade(validationEngine, gameFlow, history, freeSpin, cheats, idempotency, round, gaming, stateCache), nil
 			},
 		},
 	}
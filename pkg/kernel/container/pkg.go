@@ -4,13 +4,16 @@ import (
 	"bitbucket.org/play-workspace/base-slot-server/pkg/cryptolut_rgs"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/ip2country"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/jackpot"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/config"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/constants"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/validator"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/overlord"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rounds"
 	"fmt"
 	"github.com/sarulabs/di"
+	"go.uber.org/zap"
 	"strings"
 	"time"
 )
@@ -64,6 +67,32 @@ func BuildPkg() []di.Def {
 				return history.NewClient(cfg.HistoryConfig)
 			},
 		},
+		{
+			Name: constants.JackpotName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(constants.ConfigName).(*config.Config)
+
+				if cfg.JackpotMongoDBConfig != nil {
+					return jackpot.NewMongoDBStorage(cfg.JackpotMongoDBConfig)
+				}
+
+				return jackpot.NewMemoryStorage(), nil
+			},
+		},
+		{
+			Name: constants.RoundsName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(constants.ConfigName).(*config.Config)
+
+				if cfg.RoundsMongoDBConfig != nil {
+					return rounds.NewMongoDBStorage(cfg.RoundsMongoDBConfig)
+				}
+
+				zap.S().Warn("rounds are kept in memory, the unconfirmed rounds are lost on restart: set roundsMongoDB")
+
+				return rounds.NewMemoryStorage(), nil
+			},
+		},
 		{
 			Name: constants.RNGName,
 			Build: func(ctn di.Container) (interface{}, error) {
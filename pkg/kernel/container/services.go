@@ -2,9 +2,13 @@ package container
 
 import (
 	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/jackpot"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/config"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/constants"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/services"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/overlord"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rounds"
 	"github.com/sarulabs/di"
 )
 
@@ -16,8 +20,22 @@ func BuildServices() []di.Def {
 				lordClint := ctn.Get(constants.OverlordName).(overlord.Client)
 				historySrv := ctn.Get(constants.HistoryServiceName).(*services.HistoryService)
 				cheatsSrv := ctn.Get(constants.CheatsServiceName).(*services.CheatsService)
+				jackpotSrv := ctn.Get(constants.JackpotServiceName).(*services.JackpotService)
+				roundSrv := ctn.Get(constants.RoundServiceName).(*services.RoundService)
 
-				return services.NewGameFlowService(lordClint, historySrv, cheatsSrv), nil
+				return services.NewGameFlowService(lordClint, historySrv, cheatsSrv, jackpotSrv, roundSrv), nil
+			},
+		},
+		{
+			Name: constants.RoundServiceName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				storage := ctn.Get(constants.RoundsName).(rounds.Storage)
+				lordClint := ctn.Get(constants.OverlordName).(overlord.Client)
+				historySrv := ctn.Get(constants.HistoryServiceName).(*services.HistoryService)
+				idempotencySrv := ctn.Get(constants.IdempotencyServiceName).(*services.IdempotencyService)
+				jackpotSrv := ctn.Get(constants.JackpotServiceName).(*services.JackpotService)
+
+				return services.NewRoundService(storage, lordClint, historySrv, idempotencySrv, jackpotSrv), nil
 			},
 		},
 		{
@@ -31,7 +49,14 @@ func BuildServices() []di.Def {
 		{
 			Name: constants.SimulatorServiceName,
 			Build: func(ctn di.Container) (interface{}, error) {
-				return services.NewSimulatorService(), nil
+				cfg := ctn.Get(constants.ConfigName).(*config.Config)
+
+				jobs, err := services.NewSimulationJobStore(cfg.SimulatorJobsConfig)
+				if err != nil {
+					return nil, err
+				}
+
+				return services.NewSimulatorService(jobs, cfg.SimulatorJobsConfig.InstanceName()), nil
 			},
 		},
 		{
@@ -48,5 +73,56 @@ func BuildServices() []di.Def {
 				return services.NewCheatsService(), nil
 			},
 		},
+		{
+			Name: constants.IdempotencyServiceName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(constants.ConfigName).(*config.Config)
+
+				srv := services.NewIdempotencyService(cfg.IdempotencyConfig)
+				srv.Start()
+
+				return srv, nil
+			},
+			Close: func(obj interface{}) error {
+				obj.(*services.IdempotencyService).Stop()
+
+				return nil
+			},
+		},
+		{
+			Name: constants.GamingServiceName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(constants.ConfigName).(*config.Config)
+
+				srv := services.NewResponsibleGamingService(cfg.ResponsibleGamingConfig)
+				srv.Start()
+
+				return srv, nil
+			},
+			Close: func(obj interface{}) error {
+				obj.(*services.ResponsibleGamingService).Stop()
+
+				return nil
+			},
+		},
+		{
+			Name: constants.GameStateCacheName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(constants.ConfigName).(*config.Config)
+
+				return services.NewGameStateCache(cfg.GameStateCacheConfig, engine.GetFromContainer().SpinFactory), nil
+			},
+			Close: func(obj interface{}) error {
+				return obj.(services.GameStateCache).Close()
+			},
+		},
+		{
+			Name: constants.JackpotServiceName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				storage := ctn.Get(constants.JackpotName).(jackpot.Storage)
+
+				return services.NewJackpotService(storage), nil
+			},
+		},
 	}
 }
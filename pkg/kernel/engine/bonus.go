@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type BonusStatus string
+
+const (
+	BonusNotTriggered BonusStatus = ""
+	BonusTriggered    BonusStatus = "triggered"
+	BonusPlaying      BonusStatus = "playing"
+	BonusFinished     BonusStatus = "finished"
+)
+
+var (
+	ErrBonusAlreadyTriggered = errors.New("bonus round is already triggered")
+	ErrBonusNotActive        = errors.New("bonus round is not active")
+)
+
+// BonusResult is a game specific free spin result, DeepCopy must not share slices and maps with the result.
+type BonusResult[T any] interface {
+	DeepCopy() T
+}
+
+// BonusRound is a free spins round state machine: trigger -> play N spins (with retriggers) -> finish.
+// T is a game specific free spin result. Keep it in the spin as a named field, so it is serialized with the spin:
+//
+//	type Spin struct {
+//		Bonus engine.BonusRound[FreeSpin] `json:"bonus"`
+//	}
+//
+//	func (s FreeSpin) DeepCopy() FreeSpin { return FreeSpin{Window: utils.DeepCopy2D(s.Window)} }
+//
+//	func (s *Spin) BonusAward() int64     { return s.Bonus.TotalAward() }
+//	func (s *Spin) BonusTriggered() bool  { return s.Bonus.Triggered() }
+//	func (s *Spin) BonusSpinsCount() int  { return s.Bonus.PlayedSpins() }
+//
+// In SpinFactory.KeepGenerate call Next while Active returns true and return Active as the "can continue" flag.
+type BonusRound[T BonusResult[T]] struct {
+	Status     BonusStatus `json:"status"`
+	Total      int         `json:"total"` // awarded spins including retriggers
+	Retriggers int         `json:"retriggers"`
+	Multiplier int64       `json:"multiplier"`
+	Award      int64       `json:"award"` // accumulated award of the played spins
+
+	Spins []BonusSpin[T] `json:"spins"`
+}
+
+type BonusSpin[T any] struct {
+	Spin       T     `json:"spin"`
+	Award      int64 `json:"award"`      // award with multiplier
+	Multiplier int64 `json:"multiplier"` // multiplier applied to the spin
+	Retrigger  int   `json:"retrigger"`  // spins added by this spin
+}
+
+// BonusStep generates a single free spin: index is the index of the spin in the round.
+// It returns the spin, its award without round multiplier and the number of retriggered spins.
+type BonusStep[T any] func(index int, multiplier int64) (spin T, award int64, retrigger int, err error)
+
+// Trigger starts the round with the number of spins.
+func (b *BonusRound[T]) Trigger(spins int) error {
+	if b.Active() {
+		return ErrBonusAlreadyTriggered
+	}
+
+	if spins <= 0 {
+		return fmt.Errorf("bonus round must have positive number of spins, got %d", spins)
+	}
+
+	*b = BonusRound[T]{Status: BonusTriggered, Total: spins, Multiplier: 1}
+
+	return nil
+}
+
+// Retrigger adds spins to the active round.
+func (b *BonusRound[T]) Retrigger(spins int) error {
+	if !b.Active() {
+		return ErrBonusNotActive
+	}
+
+	if spins > 0 {
+		b.Total += spins
+		b.Retriggers++
+	}
+
+	return nil
+}
+
+// SetMultiplier sets the multiplier carried to the next spins of the round.
+func (b *BonusRound[T]) SetMultiplier(multiplier int64) {
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+
+	b.Multiplier = multiplier
+}
+
+// Next plays a single free spin, it is used in SpinFactory.KeepGenerate when the round is played step by step.
+func (b *BonusRound[T]) Next(step BonusStep[T]) error {
+	if !b.Active() {
+		return ErrBonusNotActive
+	}
+
+	spin, award, retrigger, err := step(len(b.Spins), b.Multiplier)
+	if err != nil {
+		return err
+	}
+
+	item := BonusSpin[T]{Spin: spin, Award: award * b.Multiplier, Multiplier: b.Multiplier, Retrigger: retrigger}
+
+	b.Spins = append(b.Spins, item)
+	b.Award += item.Award
+	b.Status = BonusPlaying
+
+	if err := b.Retrigger(retrigger); err != nil {
+		return err
+	}
+
+	if b.Left() == 0 {
+		b.Status = BonusFinished
+	}
+
+	return nil
+}
+
+// PlayAll plays all the remaining spins, it is used in SpinFactory.Generate when the round is generated at once.
+func (b *BonusRound[T]) PlayAll(step BonusStep[T]) error {
+	for b.Active() {
+		if err := b.Next(step); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Active returns true if the round has spins to play, SpinFactory.KeepGenerate can be continued.
+func (b *BonusRound[T]) Active() bool {
+	return b.Status == BonusTriggered || b.Status == BonusPlaying
+}
+
+func (b *BonusRound[T]) Triggered() bool {
+	return b.Status != BonusNotTriggered
+}
+
+func (b *BonusRound[T]) Finished() bool {
+	return b.Status == BonusFinished
+}
+
+func (b *BonusRound[T]) Left() int {
+	return b.Total - len(b.Spins)
+}
+
+// TotalAward is the bonus award of the round, use it in Spin.BonusAward.
+func (b *BonusRound[T]) TotalAward() int64 {
+	return b.Award
+}
+
+// PlayedSpins is the number of played free spins, it is used by BonusRestoringIndexes.
+func (b *BonusRound[T]) PlayedSpins() int {
+	return len(b.Spins)
+}
+
+func (b *BonusRound[T]) DeepCopy() BonusRound[T] {
+	cp := *b
+	cp.Spins = nil
+
+	for _, spin := range b.Spins {
+		spin.Spin = spin.Spin.DeepCopy()
+		cp.Spins = append(cp.Spins, spin)
+	}
+
+	return cp
+}
+
+// BonusSpinsCounter is implemented by spins with a bonus round, it is used by BonusRestoringIndexes.
+type BonusSpinsCounter interface {
+	BonusSpinsCount() int
+}
+
+// BonusRestoringIndexes is RestoringIndexes for games with a bonus round:
+// the spin is shown when the base spin and all the free spins were shown.
+type BonusRestoringIndexes struct {
+	BaseSpinShown  bool `json:"base_spin_shown"`
+	FreeSpinsShown int  `json:"free_spins_shown"`
+}
+
+func (r *BonusRestoringIndexes) IsShown(spin Spin) bool {
+	if !r.BaseSpinShown {
+		return false
+	}
+
+	counter, ok := spin.(BonusSpinsCounter)
+	if !ok || !spin.BonusTriggered() {
+		return true
+	}
+
+	return r.FreeSpinsShown >= counter.BonusSpinsCount()
+}
+
+func (r *BonusRestoringIndexes) Update(payload interface{}) error {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, r)
+}
@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type freeSpin struct {
+	Window []int `json:"window"`
+}
+
+func (s freeSpin) DeepCopy() freeSpin {
+	return freeSpin{Window: append([]int(nil), s.Window...)}
+}
+
+// bonusSpin is a spin with the bonus round for the restoring indexes.
+type bonusSpin struct {
+	testSpin
+	Bonus BonusRound[freeSpin] `json:"bonus"`
+}
+
+func (s *bonusSpin) BonusTriggered() bool { return s.Bonus.Triggered() }
+func (s *bonusSpin) BonusSpinsCount() int { return s.Bonus.PlayedSpins() }
+
+// steps returns the step which plays the awards and retriggers by the index of the spin.
+func steps(awards []int64, retriggers map[int]int) BonusStep[freeSpin] {
+	return func(index int, multiplier int64) (freeSpin, int64, int, error) {
+		return freeSpin{Window: []int{index}}, awards[index], retriggers[index], nil
+	}
+}
+
+func TestBonusRound_Trigger(t *testing.T) {
+	var b BonusRound[freeSpin]
+
+	require.False(t, b.Triggered())
+	require.ErrorIs(t, b.Next(steps(nil, nil)), ErrBonusNotActive)
+	require.ErrorIs(t, b.Retrigger(1), ErrBonusNotActive)
+	require.Error(t, b.Trigger(0))
+
+	require.NoError(t, b.Trigger(3))
+	require.True(t, b.Triggered())
+	require.True(t, b.Active())
+	require.Equal(t, 3, b.Left())
+	require.Equal(t, int64(1), b.Multiplier)
+
+	require.ErrorIs(t, b.Trigger(3), ErrBonusAlreadyTriggered)
+}
+
+func TestBonusRound_PlayAll(t *testing.T) {
+	var b BonusRound[freeSpin]
+
+	require.NoError(t, b.Trigger(2))
+
+	// the retrigger on the last spin continues the round
+	require.NoError(t, b.PlayAll(steps([]int64{10, 20, 30, 40}, map[int]int{1: 2})))
+
+	require.True(t, b.Finished())
+	require.False(t, b.Active())
+	require.Equal(t, 4, b.Total)
+	require.Equal(t, 1, b.Retriggers)
+	require.Equal(t, 4, b.PlayedSpins())
+	require.Equal(t, 0, b.Left())
+	require.Equal(t, int64(100), b.TotalAward())
+	require.ErrorIs(t, b.Next(steps(nil, nil)), ErrBonusNotActive)
+}
+
+func TestBonusRound_Multiplier(t *testing.T) {
+	var b BonusRound[freeSpin]
+
+	require.NoError(t, b.Trigger(3))
+
+	step := steps([]int64{10, 10, 10}, nil)
+
+	require.NoError(t, b.Next(step))
+	b.SetMultiplier(3)
+	require.NoError(t, b.Next(step))
+	b.SetMultiplier(0)
+	require.NoError(t, b.Next(step))
+
+	require.Equal(t, []int64{10, 30, 10}, []int64{b.Spins[0].Award, b.Spins[1].Award, b.Spins[2].Award})
+	require.Equal(t, []int64{1, 3, 1}, []int64{b.Spins[0].Multiplier, b.Spins[1].Multiplier, b.Spins[2].Multiplier})
+	require.Equal(t, int64(50), b.TotalAward())
+	require.Equal(t, BonusFinished, b.Status)
+}
+
+func TestBonusRound_StepError(t *testing.T) {
+	var b BonusRound[freeSpin]
+
+	require.NoError(t, b.Trigger(1))
+
+	stepErr := errors.New("step error")
+
+	err := b.Next(func(int, int64) (freeSpin, int64, int, error) {
+		return freeSpin{}, 0, 0, stepErr
+	})
+
+	require.ErrorIs(t, err, stepErr)
+	require.Equal(t, 0, b.PlayedSpins())
+	require.True(t, b.Active())
+}
+
+func TestBonusRound_DeepCopy(t *testing.T) {
+	var b BonusRound[freeSpin]
+
+	require.NoError(t, b.Trigger(2))
+	require.NoError(t, b.Next(steps([]int64{10, 10}, nil)))
+
+	cp := b.DeepCopy()
+	cp.Spins[0].Spin.Window[0] = 100
+	require.NoError(t, cp.Next(steps([]int64{10, 10}, nil)))
+
+	require.Equal(t, []int{0}, b.Spins[0].Spin.Window)
+	require.Equal(t, 1, b.PlayedSpins())
+	require.True(t, b.Active())
+}
+
+func TestBonusRestoringIndexes(t *testing.T) {
+	spin := &bonusSpin{}
+	require.NoError(t, spin.Bonus.Trigger(2))
+	require.NoError(t, spin.Bonus.PlayAll(steps([]int64{10, 10}, nil)))
+
+	r := &BonusRestoringIndexes{}
+	require.False(t, r.IsShown(spin))
+
+	require.NoError(t, r.Update(map[string]interface{}{"base_spin_shown": true, "free_spins_shown": 1}))
+	require.Equal(t, BonusRestoringIndexes{BaseSpinShown: true, FreeSpinsShown: 1}, *r)
+	require.False(t, r.IsShown(spin))
+
+	require.NoError(t, r.Update(map[string]interface{}{"free_spins_shown": 2}))
+	require.True(t, r.BaseSpinShown)
+	require.True(t, r.IsShown(spin))
+
+	// the spin without the bonus is shown with the base spin
+	require.True(t, r.IsShown(&bonusSpin{}))
+	require.Error(t, r.Update(map[string]interface{}{"free_spins_shown": "all"}))
+}
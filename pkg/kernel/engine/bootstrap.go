@@ -14,10 +14,17 @@ type Bootstrap struct {
 	FreeSpinsFeature    bool `mapstructure:"free_spins_feature"`
 	GambleAnyWinFeature bool `mapstructure:"gamble_any_win_feature"`
 
-	AnteBetMultiplier int64 `mapstructure:"ante_bet_multiplier"` // if ante bet value 1.25, you should set as 125
-	ChainDependency   bool  `mapstructure:"chain_dependency"`
+	AnteBetMultiplier      int64       `mapstructure:"ante_bet_multiplier"`      // if ante bet value 1.25, you should set as 125
+	DoubleChanceMultiplier int64       `mapstructure:"double_chance_multiplier"` // the same as ante bet multiplier
+	BuyOptions             []BuyOption `mapstructure:"buy_options"`
+	ChainDependency        bool        `mapstructure:"chain_dependency"`
 
 	GameMaxWager int64 `mapstructure:"game_max_wager"`
+	// MaxWinMultiplier caps the round award to wager * MaxWinMultiplier,
+	// 0 means DefaultMaxWinMultiplier and a negative value means there is no cap.
+	MaxWinMultiplier int64 `mapstructure:"max_win_multiplier"`
+
+	Jackpots []JackpotTier `mapstructure:"jackpots"`
 
 	HistoryHandlingType HistoryType `mapstructure:"-"`
 
@@ -2,21 +2,99 @@ package engine
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 
 	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
 )
 
 const (
-	Two int64 = 2
+	Two  int64 = 2
+	Four int64 = 4
+)
+
+const (
+	ColorGamble       = "color" // red or black, default
+	SuitGamble        = "suit"  // one of four suits
+	HigherLowerGamble = "higher_lower"
+	LadderGamble      = "ladder"
+	HalfCollectGamble = "half_collect"
+)
+
+const (
+	LowerPick  uint64 = 0
+	HigherPick uint64 = 1
+	tiePick    uint64 = 2 // the next card is equal to the shown one, none of the picks wins
+
+	cardsInSuit uint64 = 13
+)
+
+var (
+	ErrGambleLost          = errors.New("previous gamble was lost")
+	ErrGambleLimitReached  = errors.New("gamble double up limit is reached")
+	ErrGambleMaxWinReached = errors.New("gamble max win is reached")
+	ErrNoWinToGamble       = errors.New("no win to gamble")
+	ErrUnknownGambleType   = errors.New("unknown gamble type")
+	ErrInvalidGamblePick   = errors.New("invalid gamble pick")
 )
 
 type GambleConfig struct {
-	DoubleUpLimit int `json:"double_up_limit"`
+	DoubleUpLimit int   `json:"double_up_limit"` // 0 means no limit
+	MaxWin        int64 `json:"max_win"`         // gamble award cap, 0 means no cap
+	Stake         int64 `json:"stake"`           // paid base award of the spin, 0 means spin.BaseAward()
 }
 
 type GambleParams struct {
-	GamblePick *uint64 `json:"gamble_pick"` // zero or one
+	GamblePick *uint64 `json:"gamble_pick"` // depends on gamble type: 0 or 1 for color, 0-3 for suit, LowerPick or HigherPick
+	GambleType string  `json:"gamble_type"` // empty means ColorGamble
+}
+
+// GambleType is a gamble variant. Register game specific variants with RegisterGambleType.
+type GambleType interface {
+	// Multiplier is the payout multiplier of the won gamble.
+	Multiplier() int64
+	ValidatePick(pick uint64) error
+	// Draw sets the expected pick of the item, last is the previous gamble of the round or nil.
+	// cheats are the raw engine context cheats, every type parses its own ones.
+	Draw(rng rng.Client, item, last *GambleItem, cheats any) error
+	// Award computes the award of the drawn item.
+	Award(item *GambleItem) int64
+}
+
+var (
+	gambleTypes = map[string]GambleType{
+		ColorGamble:       &pickGamble{options: 2, multiplier: Two},
+		SuitGamble:        &pickGamble{options: 4, multiplier: Four},
+		HigherLowerGamble: &higherLowerGamble{},
+		LadderGamble:      &ladderGamble{pickGamble{options: 2, multiplier: Two}},
+		HalfCollectGamble: &halfCollectGamble{pickGamble{options: 2, multiplier: Two}},
+	}
+	gambleTypesMu sync.RWMutex
+)
+
+// RegisterGambleType adds a new gamble variant or overrides the existing one, call it on the game bootstrap.
+func RegisterGambleType(name string, gambleType GambleType) {
+	gambleTypesMu.Lock()
+	defer gambleTypesMu.Unlock()
+
+	gambleTypes[name] = gambleType
+}
+
+func GetGambleType(name string) (GambleType, error) {
+	if name == "" {
+		name = ColorGamble
+	}
+
+	gambleTypesMu.RLock()
+	gambleType, ok := gambleTypes[name]
+	gambleTypesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGambleType, name)
+	}
+
+	return gambleType, nil
 }
 
 func ParseGambleCheats(cheats any) (*uint64, error) {
@@ -33,6 +111,30 @@ func ParseGambleCheats(cheats any) (*uint64, error) {
 	return gc.GamblePick, nil
 }
 
+type HigherLowerCheats struct {
+	Card     *uint64 `json:"gamble_card"`
+	NextCard *uint64 `json:"gamble_next_card"`
+}
+
+func ParseHigherLowerCheats(cheats any) (hc HigherLowerCheats, err error) {
+	b, err := json.Marshal(cheats)
+	if err != nil {
+		return hc, err
+	}
+
+	if err := json.Unmarshal(b, &hc); err != nil {
+		return hc, err
+	}
+
+	for _, card := range []*uint64{hc.Card, hc.NextCard} {
+		if card != nil && (*card == 0 || *card > cardsInSuit) {
+			return hc, fmt.Errorf("card must be from 1 to %d", cardsInSuit)
+		}
+	}
+
+	return hc, nil
+}
+
 func ParseAndValidateGambleParams(parameters interface{}) (gp GambleParams, err error) {
 	b, err := json.Marshal(parameters)
 	if err != nil {
@@ -47,18 +149,28 @@ func ParseAndValidateGambleParams(parameters interface{}) (gp GambleParams, err
 }
 
 func ValidateGambleParams(gp GambleParams) error {
-	if gp.GamblePick != nil && *gp.GamblePick != 0 && *gp.GamblePick != 1 {
-		return fmt.Errorf("pick must be 0 or 1")
+	gambleType, err := GetGambleType(gp.GambleType)
+	if err != nil {
+		return err
+	}
+
+	if gp.GamblePick != nil {
+		return gambleType.ValidatePick(*gp.GamblePick)
 	}
 
 	return nil
 }
 
 type GambleItem struct {
-	Wager        int64  `json:"wager"`
-	Award        int64  `json:"award"`
-	UserPick     uint64 `json:"user_pick"`     // zero or one
-	ExpectedPick uint64 `json:"expected_pick"` // zero or one
+	Type          string `json:"type,omitempty"`
+	Wager         int64  `json:"wager"`
+	Award         int64  `json:"award"`
+	UserPick      uint64 `json:"user_pick"`
+	ExpectedPick  uint64 `json:"expected_pick"`
+	Collected     int64  `json:"collected,omitempty"` // part of the wager taken by the player before gambling
+	Card          uint64 `json:"card,omitempty"`      // shown card of the higher/lower gamble
+	NextCard      uint64 `json:"next_card,omitempty"` // drawn card of the higher/lower gamble
+	MaxWinReached bool   `json:"max_win_reached,omitempty"`
 }
 
 func (g *GambleItem) isWin() bool {
@@ -67,15 +179,31 @@ func (g *GambleItem) isWin() bool {
 
 type Gamble []*GambleItem
 
+// Play plays the gamble without limits, use PlayWithConfig to enforce them.
 func (g *Gamble) Play(rng rng.Client, spin Spin, params, cheats any) (err error) {
+	return g.PlayWithConfig(rng, spin, params, cheats, GambleConfig{})
+}
+
+func (g *Gamble) PlayWithConfig(rng rng.Client, spin Spin, params, cheats any, cfg GambleConfig) (err error) {
 	if g.lose() {
-		return fmt.Errorf("you losed previous gamble")
+		return ErrGambleLost
+	}
+
+	if last := g.last(); last != nil && last.MaxWinReached {
+		return ErrGambleMaxWinReached
+	}
+
+	if cfg.DoubleUpLimit > 0 && g.Len() >= cfg.DoubleUpLimit {
+		return ErrGambleLimitReached
 	}
 
 	wager := spin.BaseAward()
+	if cfg.Stake > 0 {
+		wager = cfg.Stake
+	}
 
 	if wager == 0 {
-		return fmt.Errorf("no win to gamble")
+		return ErrNoWinToGamble
 	}
 
 	gambleParams, err := ParseAndValidateGambleParams(params)
@@ -83,31 +211,37 @@ func (g *Gamble) Play(rng rng.Client, spin Spin, params, cheats any) (err error)
 		return err
 	}
 
-	var expectedPick *uint64
+	if gambleParams.GamblePick == nil {
+		return fmt.Errorf("%w: gamble pick is required", ErrInvalidGamblePick)
+	}
 
-	if cheats != nil {
-		expectedPick, err = ParseGambleCheats(cheats)
-		if err != nil {
-			return err
-		}
+	gambleType, err := GetGambleType(gambleParams.GambleType)
+	if err != nil {
+		return err
 	}
 
-	if expectedPick == nil {
-		ep, err := rng.Rand(2)
-		if err != nil {
-			return err
-		}
+	gi := &GambleItem{
+		Type:     gambleParams.GambleType,
+		Wager:    wager,
+		UserPick: *gambleParams.GamblePick,
+	}
 
-		expectedPick = &ep
+	if last := g.last(); last != nil {
+		gi.Wager = last.Award
 	}
 
-	gi := &GambleItem{
-		Wager:        wager,
-		UserPick:     *gambleParams.GamblePick,
-		ExpectedPick: *expectedPick,
+	if err := gambleType.Draw(rng, gi, g.last(), cheats); err != nil {
+		return err
+	}
+
+	gi.Award = gambleType.Award(gi)
+
+	if cfg.MaxWin > 0 && gi.Award >= cfg.MaxWin {
+		gi.Award = cfg.MaxWin
+		gi.MaxWinReached = true
 	}
 
-	g.compute(gi)
+	*g = append(*g, gi)
 
 	return nil
 }
@@ -142,24 +276,19 @@ func (g *Gamble) Wager() int64 {
 	return last.Wager
 }
 
-func (g *Gamble) lose() bool {
-	last := g.last()
+// Closed returns true if the player can not gamble anymore: the last gamble was lost or the max win is reached.
+func (g *Gamble) Closed() bool {
+	if g.Len() == 0 {
+		return false
+	}
 
-	return last != nil && !last.isWin()
+	return g.lose() || g.last().MaxWinReached
 }
 
-func (g *Gamble) compute(current *GambleItem) {
+func (g *Gamble) lose() bool {
 	last := g.last()
 
-	if last != nil {
-		current.Wager = last.Award
-	}
-
-	if current.isWin() {
-		current.Award = current.Wager * Two
-	}
-
-	*g = append(*g, current)
+	return last != nil && !last.isWin()
 }
 
 func (g *Gamble) last() *GambleItem {
@@ -177,3 +306,165 @@ func (g *Gamble) beforeLast() *GambleItem {
 
 	return (*g)[len(*g)-2]
 }
+
+// pickGamble is the player picks one of the equally likely options: color or suit.
+type pickGamble struct {
+	options    uint64
+	multiplier int64
+}
+
+func (p *pickGamble) Multiplier() int64 {
+	return p.multiplier
+}
+
+func (p *pickGamble) ValidatePick(pick uint64) error {
+	if pick >= p.options {
+		return fmt.Errorf("%w: pick must be from 0 to %d", ErrInvalidGamblePick, p.options-1)
+	}
+
+	return nil
+}
+
+func (p *pickGamble) Draw(rng rng.Client, item, _ *GambleItem, cheats any) error {
+	var expectedPick *uint64
+
+	if cheats != nil {
+		var err error
+
+		expectedPick, err = ParseGambleCheats(cheats)
+		if err != nil {
+			return err
+		}
+
+		if expectedPick != nil {
+			if err = p.ValidatePick(*expectedPick); err != nil {
+				return err
+			}
+		}
+	}
+
+	if expectedPick == nil {
+		ep, err := rng.Rand(p.options)
+		if err != nil {
+			return err
+		}
+
+		expectedPick = &ep
+	}
+
+	item.ExpectedPick = *expectedPick
+
+	return nil
+}
+
+func (p *pickGamble) Award(item *GambleItem) int64 {
+	if item.isWin() {
+		return item.Wager * p.multiplier
+	}
+
+	return 0
+}
+
+// higherLowerGamble is the player guesses if the next card is higher or lower than the shown one.
+// The drawn card becomes the shown card of the next higher/lower gamble, equal cards lose.
+type higherLowerGamble struct{}
+
+func (h *higherLowerGamble) Multiplier() int64 {
+	return Two
+}
+
+func (h *higherLowerGamble) ValidatePick(pick uint64) error {
+	if pick != LowerPick && pick != HigherPick {
+		return fmt.Errorf("%w: pick must be %d (lower) or %d (higher)", ErrInvalidGamblePick, LowerPick, HigherPick)
+	}
+
+	return nil
+}
+
+func (h *higherLowerGamble) Draw(rng rng.Client, item, last *GambleItem, cheats any) (err error) {
+	var hc HigherLowerCheats
+
+	if cheats != nil {
+		if hc, err = ParseHigherLowerCheats(cheats); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case last != nil && last.Type == HigherLowerGamble:
+		item.Card = last.NextCard
+	case hc.Card != nil:
+		item.Card = *hc.Card
+	default:
+		if item.Card, err = drawCard(rng); err != nil {
+			return err
+		}
+	}
+
+	if hc.NextCard != nil {
+		item.NextCard = *hc.NextCard
+	} else if item.NextCard, err = drawCard(rng); err != nil {
+		return err
+	}
+
+	switch {
+	case item.NextCard > item.Card:
+		item.ExpectedPick = HigherPick
+	case item.NextCard < item.Card:
+		item.ExpectedPick = LowerPick
+	default:
+		item.ExpectedPick = tiePick
+	}
+
+	return nil
+}
+
+func (h *higherLowerGamble) Award(item *GambleItem) int64 {
+	if item.isWin() {
+		return item.Wager * Two
+	}
+
+	return 0
+}
+
+func drawCard(rng rng.Client) (uint64, error) {
+	card, err := rng.Rand(cardsInSuit)
+	if err != nil {
+		return 0, err
+	}
+
+	return card + 1, nil
+}
+
+// ladderGamble is a color gamble where the loss drops the player one step down the ladder (half of the wager)
+// instead of losing everything. The gamble is over after the loss.
+type ladderGamble struct {
+	pickGamble
+}
+
+func (l *ladderGamble) Award(item *GambleItem) int64 {
+	if item.isWin() {
+		return item.Wager * l.multiplier
+	}
+
+	return item.Wager / Two
+}
+
+// halfCollectGamble is a color gamble where the player collects half of the wager and gambles the rest.
+type halfCollectGamble struct {
+	pickGamble
+}
+
+func (h *halfCollectGamble) Draw(rng rng.Client, item, last *GambleItem, cheats any) error {
+	item.Collected = item.Wager / Two
+
+	return h.pickGamble.Draw(rng, item, last, cheats)
+}
+
+func (h *halfCollectGamble) Award(item *GambleItem) int64 {
+	return item.Collected + h.pickGamble.Award(&GambleItem{
+		Wager:        item.Wager - item.Collected,
+		UserPick:     item.UserPick,
+		ExpectedPick: item.ExpectedPick,
+	})
+}
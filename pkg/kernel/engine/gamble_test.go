@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"sync"
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"github.com/stretchr/testify/require"
+)
+
+func pick(gambleType string, p uint64) map[string]any {
+	return map[string]any{"gamble_type": gambleType, "gamble_pick": p}
+}
+
+func gambleRng(t *testing.T) rng.Client {
+	client, err := rng.NewMockClient(nil)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestGamble_Variants(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]any
+		cheats    map[string]any
+		award     int64
+		collected int64
+	}{
+		{name: "color win", params: pick("", 1), cheats: map[string]any{"gamble_pick": 1}, award: 200},
+		{name: "color lose", params: pick(ColorGamble, 0), cheats: map[string]any{"gamble_pick": 1}, award: 0},
+		{name: "suit win", params: pick(SuitGamble, 3), cheats: map[string]any{"gamble_pick": 3}, award: 400},
+		{name: "suit lose", params: pick(SuitGamble, 2), cheats: map[string]any{"gamble_pick": 3}, award: 0},
+		{
+			name: "higher win", params: pick(HigherLowerGamble, HigherPick),
+			cheats: map[string]any{"gamble_card": 5, "gamble_next_card": 9}, award: 200,
+		},
+		{
+			name: "lower lose", params: pick(HigherLowerGamble, LowerPick),
+			cheats: map[string]any{"gamble_card": 5, "gamble_next_card": 9}, award: 0,
+		},
+		{
+			name: "equal cards lose", params: pick(HigherLowerGamble, HigherPick),
+			cheats: map[string]any{"gamble_card": 5, "gamble_next_card": 5}, award: 0,
+		},
+		{name: "ladder win", params: pick(LadderGamble, 0), cheats: map[string]any{"gamble_pick": 0}, award: 200},
+		{name: "ladder lose drops a step", params: pick(LadderGamble, 0), cheats: map[string]any{"gamble_pick": 1}, award: 50},
+		{
+			name: "half collect win", params: pick(HalfCollectGamble, 1),
+			cheats: map[string]any{"gamble_pick": 1}, award: 150, collected: 50,
+		},
+		{
+			name: "half collect lose", params: pick(HalfCollectGamble, 1),
+			cheats: map[string]any{"gamble_pick": 0}, award: 50, collected: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Gamble{}
+
+			require.NoError(t, g.Play(gambleRng(t), &testSpin{Base: 100}, tt.params, tt.cheats))
+			require.Equal(t, 1, g.Len())
+			require.Equal(t, tt.award, g.Award())
+			require.Equal(t, int64(100), g.Last().Wager)
+			require.Equal(t, tt.collected, g.Last().Collected)
+		})
+	}
+}
+
+func TestGamble_HigherLowerChain(t *testing.T) {
+	g := &Gamble{}
+	spin := &testSpin{Base: 100}
+
+	require.NoError(t, g.Play(gambleRng(t), spin, pick(HigherLowerGamble, HigherPick),
+		map[string]any{"gamble_card": 2, "gamble_next_card": 7}))
+
+	// the drawn card is shown in the next gamble, the cheat card is ignored
+	require.NoError(t, g.Play(gambleRng(t), spin, pick(HigherLowerGamble, LowerPick),
+		map[string]any{"gamble_card": 13, "gamble_next_card": 3}))
+
+	require.Equal(t, uint64(7), g.Last().Card)
+	require.Equal(t, int64(200), g.Last().Wager)
+	require.Equal(t, int64(400), g.Award())
+	require.Equal(t, int64(200), g.Wager())
+}
+
+func TestGamble_Limits(t *testing.T) {
+	spin := &testSpin{Base: 100}
+	win := map[string]any{"gamble_pick": 1}
+
+	t.Run("no win", func(t *testing.T) {
+		g := &Gamble{}
+		require.ErrorIs(t, g.Play(gambleRng(t), &testSpin{}, pick("", 1), win), ErrNoWinToGamble)
+	})
+
+	t.Run("lost", func(t *testing.T) {
+		g := &Gamble{}
+		require.NoError(t, g.Play(gambleRng(t), spin, pick("", 0), win))
+		require.True(t, g.Closed())
+		require.Equal(t, int64(100), g.Wager())
+		require.ErrorIs(t, g.Play(gambleRng(t), spin, pick("", 1), win), ErrGambleLost)
+	})
+
+	t.Run("double up limit", func(t *testing.T) {
+		g := &Gamble{}
+		cfg := GambleConfig{DoubleUpLimit: 2}
+
+		require.NoError(t, g.PlayWithConfig(gambleRng(t), spin, pick("", 1), win, cfg))
+		require.NoError(t, g.PlayWithConfig(gambleRng(t), spin, pick("", 1), win, cfg))
+		require.ErrorIs(t, g.PlayWithConfig(gambleRng(t), spin, pick("", 1), win, cfg), ErrGambleLimitReached)
+		require.Equal(t, int64(400), g.Award())
+	})
+
+	t.Run("max win", func(t *testing.T) {
+		g := &Gamble{}
+		cfg := GambleConfig{MaxWin: 300}
+
+		require.NoError(t, g.PlayWithConfig(gambleRng(t), spin, pick("", 1), win, cfg))
+		require.False(t, g.Closed())
+		require.NoError(t, g.PlayWithConfig(gambleRng(t), spin, pick("", 1), win, cfg))
+		require.True(t, g.Last().MaxWinReached)
+		require.True(t, g.Closed())
+		require.Equal(t, int64(300), g.Award())
+		require.ErrorIs(t, g.PlayWithConfig(gambleRng(t), spin, pick("", 1), win, cfg), ErrGambleMaxWinReached)
+	})
+}
+
+func TestGamble_InvalidPicks(t *testing.T) {
+	spin := &testSpin{Base: 100}
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		cheats map[string]any
+		want   error
+	}{
+		{name: "no pick", params: map[string]any{}, want: ErrInvalidGamblePick},
+		{name: "color pick", params: pick(ColorGamble, 2), want: ErrInvalidGamblePick},
+		{name: "suit pick", params: pick(SuitGamble, 4), want: ErrInvalidGamblePick},
+		{name: "higher lower pick", params: pick(HigherLowerGamble, 2), want: ErrInvalidGamblePick},
+		{name: "color cheat", params: pick(ColorGamble, 1), cheats: map[string]any{"gamble_pick": 2}, want: ErrInvalidGamblePick},
+		{name: "suit cheat", params: pick(SuitGamble, 1), cheats: map[string]any{"gamble_pick": 7}, want: ErrInvalidGamblePick},
+		{name: "unknown type", params: pick("dice", 1), want: ErrUnknownGambleType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Gamble{}
+
+			require.ErrorIs(t, g.Play(gambleRng(t), spin, tt.params, tt.cheats), tt.want)
+			require.Equal(t, 0, g.Len())
+		})
+	}
+
+	g := &Gamble{}
+	err := g.Play(gambleRng(t), spin, pick(HigherLowerGamble, HigherPick), map[string]any{"gamble_card": 14})
+	require.ErrorContains(t, err, "card must be from 1 to 13")
+}
+
+func TestRegisterGambleType(t *testing.T) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			RegisterGambleType("test_pick", &pickGamble{options: 3, multiplier: 3})
+		}()
+
+		go func() {
+			defer wg.Done()
+			_, _ = GetGambleType(ColorGamble)
+		}()
+	}
+
+	wg.Wait()
+
+	gambleType, err := GetGambleType("test_pick")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), gambleType.Multiplier())
+}
@@ -10,6 +10,8 @@ type Context struct {
 	Cheats     interface{}
 	LastSpin   Spin
 	UserParams *UserParams
+	Jackpots   *JackpotRequests // nil if the game has no jackpots
+	Purchase   *Purchase        // priced wager of the round, nil in KeepGenerate
 }
 
 type UserParams struct {
@@ -73,12 +75,28 @@ func TotalAwardWithGambling(spin Spin) int64 {
 	gambles := spin.GetGamble()
 
 	if gambles.Len() == 0 {
-		return spin.BaseAward() + spin.BonusAward()
+		return TotalAward(spin)
 	}
 
 	return gambles.Award()
 }
 
 func TotalAward(spin Spin) int64 {
-	return spin.BaseAward() + spin.BonusAward()
+	return spin.BaseAward() + spin.BonusAward() + JackpotAward(spin)
+}
+
+// SeedableSpinFactory is implemented by factories that support reproducible (seeded) simulations.
+type SeedableSpinFactory interface {
+	SpinFactory
+	// WithRngClient returns a copy of the factory that uses the client for all the random values.
+	WithRngClient(client rng.Client) SpinFactory
+}
+
+// ChoiceSpin is implemented by spins that wait for the player's choice, for example, the type of the bonus game.
+// The simulator passes one of the choices to SpinFactory.KeepGenerate as the parameters until the spin has no choices,
+// KeepGenerate returning false for an offered choice fails the simulation as it fails the round.
+type ChoiceSpin interface {
+	Spin
+	// Choices returns the available choices, empty if there is nothing to choose.
+	Choices() []any
 }
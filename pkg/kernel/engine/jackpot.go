@@ -0,0 +1,86 @@
+package engine
+
+const (
+	FixedJackpot       = "fixed"
+	ProgressiveJackpot = "progressive"
+)
+
+// JackpotTier is configured in Bootstrap.Jackpots.
+type JackpotTier struct {
+	Name string `mapstructure:"name"`
+	Type string `mapstructure:"type"`
+
+	// Multiplier is the wager multiplier of the fixed jackpot.
+	Multiplier int64 `mapstructure:"multiplier"`
+
+	// Seed is the start value of the progressive pool in the units of GameMaxWager, it is multiplied
+	// by the currency multiplier of the pool. The pool is reset to it after the win.
+	Seed int64 `mapstructure:"seed"`
+	// Contribution is the percent of every wager that goes to the progressive pool.
+	Contribution float64 `mapstructure:"contribution"`
+}
+
+type JackpotWin struct {
+	Tier  string `json:"tier"`
+	Award int64  `json:"award"`
+}
+
+// JackpotWinner is implemented by spins of games with jackpots, embed Jackpots to implement it.
+// Won jackpots are kept in the spin, so they are serialized and restored with it.
+type JackpotWinner interface {
+	JackpotWins() []JackpotWin
+	SetJackpotWins(wins []JackpotWin)
+}
+
+type Jackpots struct {
+	Wins []JackpotWin `json:"jackpot_wins,omitempty"`
+}
+
+func (j *Jackpots) JackpotWins() []JackpotWin {
+	return j.Wins
+}
+
+func (j *Jackpots) SetJackpotWins(wins []JackpotWin) {
+	j.Wins = wins
+}
+
+// JackpotAward returns the sum of won jackpots of the spin.
+func JackpotAward(spin Spin) int64 {
+	winner, ok := spin.(JackpotWinner)
+	if !ok {
+		return 0
+	}
+
+	var award int64
+
+	for _, win := range winner.JackpotWins() {
+		award += win.Award
+	}
+
+	return award
+}
+
+// JackpotRequests is passed to the engine in Context: the engine reads current values of the pools and
+// requests the won tiers, the jackpot service pays them out after the spin is generated.
+type JackpotRequests struct {
+	Values    map[string]int64 // tier -> current value
+	Requested []string
+}
+
+// RequestJackpot requests the jackpot win, it is ignored if the game has no jackpots. The tier is paid once per round.
+func (c Context) RequestJackpot(tier string) {
+	if c.Jackpots == nil {
+		return
+	}
+
+	c.Jackpots.Requested = append(c.Jackpots.Requested, tier)
+}
+
+// JackpotValue returns the current value of the tier, 0 if the game has no such jackpot.
+func (c Context) JackpotValue(tier string) int64 {
+	if c.Jackpots == nil {
+		return 0
+	}
+
+	return c.Jackpots.Values[tier]
+}
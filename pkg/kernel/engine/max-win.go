@@ -0,0 +1,63 @@
+package engine
+
+// DefaultMaxWinMultiplier is the max win of the games which do not set Bootstrap.MaxWinMultiplier.
+const DefaultMaxWinMultiplier int64 = 5000
+
+// MaxWinCapper is implemented by spins that can truncate their award to the max win cap.
+// Spins that do not implement it keep the original award, the capped award is kept next to the spin
+// in the game result and the history record.
+//
+//	func (s *Spin) CapAward(maxWin int64) {
+//		s.Award, s.Bonus.Award = engine.SplitMaxWin(s.Award, s.Bonus.Award, maxWin)
+//		s.MaxWin = true
+//	}
+//
+//	func (s *Spin) MaxWinReached() bool { return s.MaxWin }
+type MaxWinCapper interface {
+	CapAward(maxWin int64)
+	MaxWinReached() bool
+}
+
+// MaxWin returns the max award of the round for the wager, 0 means there is no cap.
+// The zero MaxWinMultiplier is DefaultMaxWinMultiplier, the negative one disables the cap.
+func (b *Bootstrap) MaxWin(wager int64) int64 {
+	switch {
+	case b.MaxWinMultiplier < 0:
+		return 0
+	case b.MaxWinMultiplier == 0:
+		return wager * DefaultMaxWinMultiplier
+	default:
+		return wager * b.MaxWinMultiplier
+	}
+}
+
+// SplitMaxWin truncates base and bonus awards, so their sum does not exceed maxWin. Base award is kept first.
+func SplitMaxWin(baseAward, bonusAward, maxWin int64) (int64, int64) {
+	if maxWin <= 0 || baseAward+bonusAward <= maxWin {
+		return baseAward, bonusAward
+	}
+
+	baseAward = min(baseAward, maxWin)
+
+	return baseAward, min(bonusAward, maxWin-baseAward)
+}
+
+// ApplyMaxWin truncates the spin award to maxWin (0 means no cap) and returns capped base and bonus awards.
+// Only MaxWinCapper spins are changed, use the returned awards for the payout and the history.
+func ApplyMaxWin(spin Spin, maxWin int64) (baseAward, bonusAward int64, reached bool) {
+	baseAward, bonusAward = spin.BaseAward(), spin.BonusAward()
+
+	if maxWin <= 0 || baseAward+bonusAward < maxWin {
+		return baseAward, bonusAward, false
+	}
+
+	if capper, ok := spin.(MaxWinCapper); ok {
+		capper.CapAward(maxWin)
+
+		return spin.BaseAward(), spin.BonusAward(), true
+	}
+
+	baseAward, bonusAward = SplitMaxWin(baseAward, bonusAward, maxWin)
+
+	return baseAward, bonusAward, true
+}
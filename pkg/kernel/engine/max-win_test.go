@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// capperSpin truncates its own awards.
+type capperSpin struct {
+	testSpin
+	reached bool
+}
+
+func (s *capperSpin) CapAward(maxWin int64) {
+	s.Base, s.Bonus = SplitMaxWin(s.Base, s.Bonus, maxWin)
+	s.reached = true
+}
+
+func (s *capperSpin) MaxWinReached() bool { return s.reached }
+
+func TestBootstrap_MaxWin(t *testing.T) {
+	require.Equal(t, int64(10*DefaultMaxWinMultiplier), (&Bootstrap{}).MaxWin(10))
+	require.Equal(t, int64(1000), (&Bootstrap{MaxWinMultiplier: 100}).MaxWin(10))
+	require.Equal(t, int64(0), (&Bootstrap{MaxWinMultiplier: -1}).MaxWin(10))
+}
+
+func TestSplitMaxWin(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, bonus, maxWin int64
+		wantBase, wantBonus int64
+	}{
+		{name: "no cap", base: 100, bonus: 200, maxWin: 0, wantBase: 100, wantBonus: 200},
+		{name: "under cap", base: 100, bonus: 200, maxWin: 300, wantBase: 100, wantBonus: 200},
+		{name: "bonus is truncated", base: 100, bonus: 200, maxWin: 250, wantBase: 100, wantBonus: 150},
+		{name: "base is truncated", base: 400, bonus: 200, maxWin: 250, wantBase: 250, wantBonus: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, bonus := SplitMaxWin(tt.base, tt.bonus, tt.maxWin)
+			require.Equal(t, tt.wantBase, base)
+			require.Equal(t, tt.wantBonus, bonus)
+		})
+	}
+}
+
+func TestApplyMaxWin(t *testing.T) {
+	t.Run("not reached", func(t *testing.T) {
+		base, bonus, reached := ApplyMaxWin(&testSpin{Base: 10, Bonus: 20}, 100)
+		require.Equal(t, []int64{10, 20}, []int64{base, bonus})
+		require.False(t, reached)
+	})
+
+	t.Run("spin without capper keeps the award", func(t *testing.T) {
+		spin := &testSpin{Base: 60, Bonus: 80}
+
+		base, bonus, reached := ApplyMaxWin(spin, 100)
+		require.Equal(t, []int64{60, 40}, []int64{base, bonus})
+		require.True(t, reached)
+		require.Equal(t, int64(80), spin.BonusAward())
+	})
+
+	t.Run("capper spin is truncated", func(t *testing.T) {
+		spin := &capperSpin{testSpin: testSpin{Base: 60, Bonus: 80}}
+
+		base, bonus, reached := ApplyMaxWin(spin, 100)
+		require.Equal(t, []int64{60, 40}, []int64{base, bonus})
+		require.True(t, reached)
+		require.True(t, spin.MaxWinReached())
+		require.Equal(t, int64(40), spin.BonusAward())
+	})
+
+	t.Run("exact max win is reached", func(t *testing.T) {
+		_, _, reached := ApplyMaxWin(&testSpin{Base: 100}, 100)
+		require.True(t, reached)
+	})
+}
+
+func TestGamble_Stake(t *testing.T) {
+	g := &Gamble{}
+
+	require.NoError(t, g.PlayWithConfig(gambleRng(t), &testSpin{Base: 1000}, pick("", 1),
+		map[string]any{"gamble_pick": 1}, GambleConfig{Stake: 100}))
+	require.Equal(t, int64(100), g.Last().Wager)
+	require.Equal(t, int64(200), g.Award())
+}
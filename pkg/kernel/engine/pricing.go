@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/samber/lo"
+)
+
+const (
+	BaseFeature         = "base"
+	AnteFeature         = "ante"
+	DoubleChanceFeature = "double_chance"
+	buyFeaturePrefix    = "buy:"
+)
+
+var (
+	ErrFeatureNotAvailable  = errors.New("feature is not available")
+	ErrFeaturesCombined     = errors.New("buy, ante and double chance can not be combined")
+	ErrUnknownBuyOption     = errors.New("unknown buy option")
+	ErrFeatureWagerMismatch = errors.New("spin wager does not match the feature price")
+)
+
+// BuyOption is a purchasable bonus configured in Bootstrap.BuyOptions.
+type BuyOption struct {
+	Name string `mapstructure:"name"`
+	// Price is the wager multiplier in percents: 10000 is 100x wager.
+	Price int64 `mapstructure:"price"`
+	// Bonus is the bonus forced by the option, the engine reads it from Context.Purchase.
+	Bonus string `mapstructure:"bonus"`
+	// Jurisdictions where the option is available, empty means everywhere.
+	Jurisdictions []string `mapstructure:"jurisdictions"`
+}
+
+func (o BuyOption) availableIn(jurisdiction string) bool {
+	return len(o.Jurisdictions) == 0 || lo.Contains(o.Jurisdictions, jurisdiction)
+}
+
+// FeatureParams are the standard keys of the wager engine params for the priced features.
+type FeatureParams struct {
+	Buy          string `json:"buy"` // BuyOption.Name
+	Ante         bool   `json:"ante"`
+	DoubleChance bool   `json:"double_chance"`
+}
+
+// PricingRules are the features enabled for the player by the operator.
+type PricingRules struct {
+	Jurisdiction string
+	BuyBonus     bool
+	DoubleChance bool
+	IsPFR        bool // no features for free bets
+
+	AllJurisdictions bool // ignore jurisdictions of buy options, it is used by the simulator
+}
+
+// Purchase is the priced wager of the round, it is passed to the engine in Context.Purchase.
+// Embed it into the spin to implement Wager and OriginalWager consistently:
+//
+//	type Spin struct {
+//		engine.Purchase
+//		...
+//	}
+//
+//	spin.Purchase = *ctx.Purchase
+type Purchase struct {
+	Buy          string `json:"buy,omitempty"`
+	Bonus        string `json:"bonus,omitempty"`
+	Ante         bool   `json:"ante,omitempty"`
+	DoubleChance bool   `json:"double_chance,omitempty"`
+
+	Original int64 `json:"original_wager"`
+	Total    int64 `json:"total_wager"`
+}
+
+func (p *Purchase) Wager() int64 {
+	return p.Total
+}
+
+func (p *Purchase) OriginalWager() int64 {
+	return p.Original
+}
+
+// Feature is the purchased feature name, it is used by the simulator to report RTP per feature.
+func (p *Purchase) Feature() string {
+	switch {
+	case p.Buy != "":
+		return buyFeaturePrefix + p.Buy
+	case p.Ante:
+		return AnteFeature
+	case p.DoubleChance:
+		return DoubleChanceFeature
+	default:
+		return BaseFeature
+	}
+}
+
+// FeatureSpin is implemented by spins embedding Purchase.
+type FeatureSpin interface {
+	Feature() string
+}
+
+// HasPricedFeatures returns true if the game is configured with buy options, ante or double chance.
+func (b *Bootstrap) HasPricedFeatures() bool {
+	return len(b.BuyOptions) > 0 || b.AnteBetMultiplier > 0 || b.DoubleChanceMultiplier > 0
+}
+
+// Price validates the feature params against the configuration and the rules and computes the wager.
+// The params of games without priced features are not decoded, their own keys may reuse the standard ones.
+func (b *Bootstrap) Price(wager int64, params interface{}, rules PricingRules) (*Purchase, error) {
+	if !b.HasPricedFeatures() {
+		return &Purchase{Original: wager, Total: wager}, nil
+	}
+
+	fp, err := UnmarshalTo[FeatureParams](params)
+	if err != nil {
+		return nil, err
+	}
+
+	purchase := &Purchase{Original: wager, Total: wager}
+
+	if lo.Count([]bool{fp.Buy != "", fp.Ante, fp.DoubleChance}, true) > 1 {
+		return nil, ErrFeaturesCombined
+	}
+
+	if rules.IsPFR && (fp.Buy != "" || fp.Ante || fp.DoubleChance) {
+		return nil, fmt.Errorf("%w: features can not be played with free bets", ErrFeatureNotAvailable)
+	}
+
+	switch {
+	case fp.Buy != "":
+		option, ok := lo.Find(b.BuyOptions, func(item BuyOption) bool { return item.Name == fp.Buy })
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownBuyOption, fp.Buy)
+		}
+
+		if !rules.BuyBonus || !(rules.AllJurisdictions || option.availableIn(rules.Jurisdiction)) {
+			return nil, fmt.Errorf("%w: buy %s", ErrFeatureNotAvailable, fp.Buy)
+		}
+
+		purchase.Buy, purchase.Bonus = option.Name, option.Bonus
+		purchase.Total = wager * option.Price / 100
+	case fp.Ante:
+		if b.AnteBetMultiplier <= 0 {
+			return nil, fmt.Errorf("%w: ante", ErrFeatureNotAvailable)
+		}
+
+		purchase.Ante = true
+		purchase.Total = wager * b.AnteBetMultiplier / 100
+	case fp.DoubleChance:
+		if b.DoubleChanceMultiplier <= 0 || !rules.DoubleChance {
+			return nil, fmt.Errorf("%w: double chance", ErrFeatureNotAvailable)
+		}
+
+		purchase.DoubleChance = true
+		purchase.Total = wager * b.DoubleChanceMultiplier / 100
+	}
+
+	return purchase, nil
+}
+
+// WagerAllowed returns false if the price of any feature is not a whole amount for the wager level.
+func (b *Bootstrap) WagerAllowed(wager int64) bool {
+	for _, multiplier := range []int64{b.AnteBetMultiplier, b.DoubleChanceMultiplier} {
+		if multiplier > 0 && wager*multiplier/100%10 != 0 {
+			return false
+		}
+	}
+
+	for _, option := range b.BuyOptions {
+		if wager*option.Price%100 != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckPurchase verifies that the engine took the price of the purchase, spins without embedded Purchase are not checked.
+func CheckPurchase(spin Spin, purchase *Purchase) error {
+	if _, ok := spin.(FeatureSpin); !ok || purchase == nil {
+		return nil
+	}
+
+	if spin.Wager() != purchase.Wager() || spin.OriginalWager() != purchase.OriginalWager() {
+		return fmt.Errorf("%w: expected %d (%d), got %d (%d)", ErrFeatureWagerMismatch,
+			purchase.Wager(), purchase.OriginalWager(), spin.Wager(), spin.OriginalWager())
+	}
+
+	return nil
+}
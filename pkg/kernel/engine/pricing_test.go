@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// purchaseSpin is a spin embedding Purchase.
+type purchaseSpin struct {
+	Spin `json:"-"`
+	Purchase
+}
+
+func (s *purchaseSpin) Wager() int64         { return s.Purchase.Wager() }
+func (s *purchaseSpin) OriginalWager() int64 { return s.Purchase.OriginalWager() }
+
+func pricedBootstrap() *Bootstrap {
+	return &Bootstrap{
+		AnteBetMultiplier:      125,
+		DoubleChanceMultiplier: 150,
+		BuyOptions: []BuyOption{
+			{Name: "free_spins", Price: 10000, Bonus: "fs"},
+			{Name: "super", Price: 25000, Bonus: "super_fs", Jurisdictions: []string{"mt"}},
+		},
+	}
+}
+
+func TestBootstrap_Price(t *testing.T) {
+	all := PricingRules{Jurisdiction: "mt", BuyBonus: true, DoubleChance: true}
+
+	tests := []struct {
+		name   string
+		params interface{}
+		rules  PricingRules
+		want   *Purchase
+		err    error
+	}{
+		{
+			name:   "base",
+			params: map[string]interface{}{"other": 1},
+			rules:  all,
+			want:   &Purchase{Original: 100, Total: 100},
+		},
+		{
+			name:   "nil params",
+			params: nil,
+			rules:  all,
+			want:   &Purchase{Original: 100, Total: 100},
+		},
+		{
+			name:   "buy",
+			params: map[string]interface{}{"buy": "free_spins"},
+			rules:  all,
+			want:   &Purchase{Buy: "free_spins", Bonus: "fs", Original: 100, Total: 10000},
+		},
+		{
+			name:   "buy in jurisdiction",
+			params: map[string]interface{}{"buy": "super"},
+			rules:  all,
+			want:   &Purchase{Buy: "super", Bonus: "super_fs", Original: 100, Total: 25000},
+		},
+		{
+			name:   "buy out of jurisdiction",
+			params: map[string]interface{}{"buy": "super"},
+			rules:  PricingRules{Jurisdiction: "uk", BuyBonus: true},
+			err:    ErrFeatureNotAvailable,
+		},
+		{
+			name:   "buy in all jurisdictions",
+			params: map[string]interface{}{"buy": "super"},
+			rules:  PricingRules{Jurisdiction: "uk", BuyBonus: true, AllJurisdictions: true},
+			want:   &Purchase{Buy: "super", Bonus: "super_fs", Original: 100, Total: 25000},
+		},
+		{
+			name:   "buy disabled by operator",
+			params: map[string]interface{}{"buy": "free_spins"},
+			rules:  PricingRules{Jurisdiction: "mt", DoubleChance: true},
+			err:    ErrFeatureNotAvailable,
+		},
+		{
+			name:   "unknown buy option",
+			params: map[string]interface{}{"buy": "missing"},
+			rules:  all,
+			err:    ErrUnknownBuyOption,
+		},
+		{
+			name:   "ante",
+			params: map[string]interface{}{"ante": true},
+			rules:  all,
+			want:   &Purchase{Ante: true, Original: 100, Total: 125},
+		},
+		{
+			name:   "double chance",
+			params: map[string]interface{}{"double_chance": true},
+			rules:  all,
+			want:   &Purchase{DoubleChance: true, Original: 100, Total: 150},
+		},
+		{
+			name:   "double chance disabled by operator",
+			params: map[string]interface{}{"double_chance": true},
+			rules:  PricingRules{BuyBonus: true},
+			err:    ErrFeatureNotAvailable,
+		},
+		{
+			name:   "combined",
+			params: map[string]interface{}{"buy": "free_spins", "ante": true},
+			rules:  all,
+			err:    ErrFeaturesCombined,
+		},
+		{
+			name:   "free bet",
+			params: map[string]interface{}{"ante": true},
+			rules:  PricingRules{Jurisdiction: "mt", BuyBonus: true, DoubleChance: true, IsPFR: true},
+			err:    ErrFeatureNotAvailable,
+		},
+		{
+			name:   "free bet without features",
+			params: map[string]interface{}{},
+			rules:  PricingRules{IsPFR: true},
+			want:   &Purchase{Original: 100, Total: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pricedBootstrap().Price(100, tt.params, tt.rules)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBootstrap_Price_TypeMismatch(t *testing.T) {
+	params := map[string]interface{}{"buy": true, "ante": "yes"}
+
+	// games without priced features keep their own meaning of the keys
+	got, err := (&Bootstrap{}).Price(100, params, PricingRules{})
+	require.NoError(t, err)
+	require.Equal(t, &Purchase{Original: 100, Total: 100}, got)
+
+	_, err = pricedBootstrap().Price(100, params, PricingRules{BuyBonus: true})
+	require.Error(t, err)
+}
+
+func TestBootstrap_WagerAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		boot  *Bootstrap
+		wager int64
+		want  bool
+	}{
+		{name: "no features", boot: &Bootstrap{}, wager: 1, want: true},
+		{name: "whole ante", boot: &Bootstrap{AnteBetMultiplier: 125}, wager: 80, want: true},
+		{name: "fractional ante", boot: &Bootstrap{AnteBetMultiplier: 125}, wager: 100, want: false},
+		{name: "fractional double chance", boot: &Bootstrap{DoubleChanceMultiplier: 150}, wager: 10, want: false},
+		{name: "whole buy", boot: &Bootstrap{BuyOptions: []BuyOption{{Price: 10050}}}, wager: 2, want: true},
+		{name: "fractional buy", boot: &Bootstrap{BuyOptions: []BuyOption{{Price: 10050}}}, wager: 1, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.boot.WagerAllowed(tt.wager))
+		})
+	}
+}
+
+func TestCheckPurchase(t *testing.T) {
+	purchase := &Purchase{Ante: true, Original: 100, Total: 125}
+
+	require.NoError(t, CheckPurchase(&purchaseSpin{Spin: &testSpin{}, Purchase: *purchase}, purchase))
+	require.ErrorIs(t, CheckPurchase(&purchaseSpin{Spin: &testSpin{}, Purchase: Purchase{Original: 100, Total: 100}}, purchase),
+		ErrFeatureWagerMismatch)
+
+	// spins without the embedded purchase and rounds without the purchase are not checked
+	require.NoError(t, CheckPurchase(&testSpin{WagerVal: 100}, purchase))
+	require.NoError(t, CheckPurchase(&purchaseSpin{Spin: &testSpin{}}, nil))
+}
+
+func TestPurchase_Feature(t *testing.T) {
+	require.Equal(t, BaseFeature, (&Purchase{}).Feature())
+	require.Equal(t, "buy:free_spins", (&Purchase{Buy: "free_spins", Ante: true}).Feature())
+	require.Equal(t, AnteFeature, (&Purchase{Ante: true}).Feature())
+	require.Equal(t, DoubleChanceFeature, (&Purchase{DoubleChance: true}).Feature())
+}
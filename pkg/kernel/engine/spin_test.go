@@ -0,0 +1,18 @@
+package engine
+
+// testSpin is a minimal Spin for the tests of the engine.
+type testSpin struct {
+	Base      int64   `json:"base"`
+	Bonus     int64   `json:"bonus"`
+	WagerVal  int64   `json:"wager"`
+	GambleVal *Gamble `json:"gamble"`
+}
+
+func (s *testSpin) BaseAward() int64                  { return s.Base }
+func (s *testSpin) BonusAward() int64                 { return s.Bonus }
+func (s *testSpin) OriginalWager() int64              { return s.WagerVal }
+func (s *testSpin) Wager() int64                      { return s.WagerVal }
+func (s *testSpin) BonusTriggered() bool              { return false }
+func (s *testSpin) GetGamble() *Gamble                { return s.GambleVal }
+func (s *testSpin) CanGamble(_ RestoringIndexes) bool { return true }
+func (s *testSpin) DeepCopy() Spin                    { cp := *s; return &cp }
@@ -0,0 +1,39 @@
+package engine
+
+const (
+	SymbolWinStat      = "symbol_win"
+	FeatureTriggerStat = "feature_trigger"
+)
+
+// Stat is a tagged statistic of the spin aggregated by the simulator.
+type Stat struct {
+	Kind   string
+	Tag    string // symbol or feature name
+	Length int    // win length, only for symbol wins
+	Award  int64
+}
+
+// SimulationStats is implemented by spins that report detailed statistics to the simulator:
+//
+//	func (s *Spin) SimulationStats() []engine.Stat {
+//		stats := lo.Map(s.PayLines, func(item utils.PayLine[int, Symbol], _ int) engine.Stat {
+//			return engine.NewSymbolWinStat(fmt.Sprint(item.PaySymbol), len(item.PayLineItems), item.Award)
+//		})
+//
+//		if s.BonusTriggered() {
+//			stats = append(stats, engine.NewFeatureTriggerStat("free_spins"))
+//		}
+//
+//		return stats
+//	}
+type SimulationStats interface {
+	SimulationStats() []Stat
+}
+
+func NewSymbolWinStat(symbol string, length int, award int64) Stat {
+	return Stat{Kind: SymbolWinStat, Tag: symbol, Length: length, Award: award}
+}
+
+func NewFeatureTriggerStat(feature string) Stat {
+	return Stat{Kind: FeatureTriggerStat, Tag: feature}
+}
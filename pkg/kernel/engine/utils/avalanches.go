@@ -1,22 +1,33 @@
 package utils
 
 type Avalanche[Symbol comparable] struct {
-	Window   [][]Symbol        `json:"window"`
-	PayItems []PayItem[Symbol] `json:"pay_items"`
+	Window     [][]Symbol        `json:"window"`
+	PayItems   []PayItem[Symbol] `json:"pay_items"`
+	Multiplier int64             `json:"multiplier,omitempty"` // progressive multiplier of the avalanche step
 }
 
 type PayItem[Symbol comparable] struct {
 	Symbol  Symbol  `json:"symbol"`
 	Indexes [][]int `json:"indexes"`
 	Award   int64   `json:"award"`
+
+	BaseAward   int64                `json:"base_award"` // award before multipliers, it is equal to Award without them
+	Multipliers *MultiplierBreakdown `json:"multipliers,omitempty"`
 }
 
 func Spin[Symbol comparable](
 	ag AwardGetter[Symbol], window AvalancheWindow[Symbol], reels []map[int]Symbol, stops []int,
+) (avalanches []Avalanche[Symbol], award int64, err error) {
+	return SpinWithMultipliers(ag, window, reels, stops, Multipliers[int, Symbol]{})
+}
+
+// SpinWithMultipliers is Spin with multiplied awards, Multipliers.Position receives reel and row indexes of the window.
+func SpinWithMultipliers[Symbol comparable](
+	ag AwardGetter[Symbol], window AvalancheWindow[Symbol], reels []map[int]Symbol, stops []int, multipliers Multipliers[int, Symbol],
 ) (avalanches []Avalanche[Symbol], award int64, err error) {
 	iReels := NewIndexedReels(reels)
 
-	for {
+	for step := 0; ; step++ {
 		var avalanche Avalanche[Symbol]
 
 		if err := window.Compute(stops, iReels.Copy(), iReels.MaxIndexes(), iReels.DeletedIndexes()); err != nil {
@@ -25,6 +36,10 @@ func Spin[Symbol comparable](
 
 		avalanche.Window = window.Matrix()
 
+		if len(multipliers.Steps) > 0 {
+			avalanche.Multiplier = multipliers.step(step)
+		}
+
 		winItems := window.CheckWin()
 
 		win := false
@@ -45,15 +60,30 @@ func Spin[Symbol comparable](
 				continue
 			}
 
-			if currentAward := ag.GetAward(symbol, qty); currentAward > 0 {
-				award += currentAward
+			currentAward := ag.GetAward(symbol, qty)
+			if wc, ok := winItems.(WaysCounter); ok {
+				currentAward *= int64(wc.GetWays())
+			}
+
+			if currentAward > 0 {
+				payItem := PayItem[Symbol]{Symbol: symbol, Indexes: winItems.GetIndexes(), Award: currentAward, BaseAward: currentAward}
+
+				reelIndexes, rows, symbols := windowSymbols(avalanche.Window, payItem.Indexes)
 
-				avalanche.PayItems = append(
-					avalanche.PayItems,
-					PayItem[Symbol]{Symbol: symbol, Indexes: winItems.GetIndexes(), Award: currentAward},
-				)
+				if m := multipliers.compute(reelIndexes, rows, symbols, step); m != nil {
+					payItem.Multipliers = m
+					payItem.Award = currentAward * m.Total
+				}
 
-				iReels.Delete(window.GetAbsIndexesBySymbol(symbol))
+				award += payItem.Award
+
+				avalanche.PayItems = append(avalanche.PayItems, payItem)
+
+				if wiw, ok := window.(WinIndexesWindow); ok {
+					iReels.Delete(wiw.GetAbsIndexes(payItem.Indexes))
+				} else {
+					iReels.Delete(window.GetAbsIndexesBySymbol(symbol))
+				}
 
 				win = true
 			}
@@ -68,3 +98,16 @@ func Spin[Symbol comparable](
 
 	return
 }
+
+// windowSymbols flattens reel -> rows indexes for Multipliers.compute.
+func windowSymbols[Symbol comparable](matrix [][]Symbol, indexes [][]int) (reelIndexes, rows []int, symbols []Symbol) {
+	for reelIndex, reelRows := range indexes {
+		for _, row := range reelRows {
+			reelIndexes = append(reelIndexes, reelIndex)
+			rows = append(rows, row)
+			symbols = append(symbols, matrix[reelIndex][row])
+		}
+	}
+
+	return
+}
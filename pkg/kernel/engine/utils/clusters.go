@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+type ClusterWin[Symbol comparable] struct {
+	Symbol  Symbol
+	Indexes [][]int // reel -> rows
+	Size    int
+}
+
+func (c *ClusterWin[Symbol]) GetSymbol() Symbol {
+	return c.Symbol
+}
+
+func (c *ClusterWin[Symbol]) GetIndexes() [][]int {
+	return c.Indexes
+}
+
+func (c *ClusterWin[Symbol]) Count() int {
+	return c.Size
+}
+
+// ClusterPays evaluates cluster wins on any [][]Symbol matrix (reel -> rows), reels can have different heights.
+// A cluster is a group of orthogonally connected symbols of the same kind, wilds join every adjacent cluster.
+// Blacklisted symbols (scatters, multipliers) never form clusters, all of their positions are returned
+// as a single win, so the avalanche loop can count them.
+// The indexes of the cluster include its wilds, the window must implement WinIndexesWindow, so the avalanche
+// deletes only the cluster and not the losing clusters of the same symbol.
+//
+//	func (w *window) CheckWin() []utils.Win[Symbol] {
+//		return w.clusterPays.CheckWin(w.Matrix())
+//	}
+//
+//	func (w *window) GetAbsIndexes(indexes [][]int) [][]int {
+//		return w.absIndexes(indexes)
+//	}
+type ClusterPays[Symbol comparable] struct {
+	Wild      *Symbol
+	MinSize   int
+	Blacklist []Symbol
+}
+
+// NewClusterPays wild is nullable
+func NewClusterPays[Symbol comparable](minSize int, wild *Symbol, blacklist ...Symbol) ClusterPays[Symbol] {
+	return ClusterPays[Symbol]{Wild: wild, MinSize: minSize, Blacklist: blacklist}
+}
+
+// CheckWin returns clusters with size >= MinSize and one win per blacklisted symbol found on the matrix.
+func (cp ClusterPays[Symbol]) CheckWin(matrix [][]Symbol) []Win[Symbol] {
+	var wins []Win[Symbol]
+
+	visited := make([][]bool, len(matrix))
+	for reelIndex := range matrix {
+		visited[reelIndex] = make([]bool, len(matrix[reelIndex]))
+	}
+
+	for reelIndex := range matrix {
+		for rowIndex, symbol := range matrix[reelIndex] {
+			if visited[reelIndex][rowIndex] || cp.isWild(symbol) || lo.Contains(cp.Blacklist, symbol) {
+				continue
+			}
+
+			cluster := cp.floodFill(matrix, visited, symbol, reelIndex, rowIndex)
+
+			if cluster.Size >= cp.MinSize {
+				wins = append(wins, cluster)
+			}
+		}
+	}
+
+	for _, symbol := range cp.Blacklist {
+		if win := collectSymbol(matrix, symbol); win.Size > 0 {
+			wins = append(wins, win)
+		}
+	}
+
+	return wins
+}
+
+// floodFill marks non-wild symbols as visited, wilds are tracked per cluster because they can be shared.
+func (cp ClusterPays[Symbol]) floodFill(matrix [][]Symbol, visited [][]bool, symbol Symbol, reelIndex, rowIndex int) *ClusterWin[Symbol] {
+	cluster := &ClusterWin[Symbol]{Symbol: symbol, Indexes: make([][]int, len(matrix))}
+	usedWilds := map[[2]int]struct{}{}
+
+	stack := [][2]int{{reelIndex, rowIndex}}
+	visited[reelIndex][rowIndex] = true
+
+	for len(stack) > 0 {
+		cell := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		cluster.Indexes[cell[0]] = append(cluster.Indexes[cell[0]], cell[1])
+		cluster.Size++
+
+		for _, next := range neighbours(matrix, cell[0], cell[1]) {
+			s := matrix[next[0]][next[1]]
+
+			switch {
+			case cp.isWild(s):
+				if _, ok := usedWilds[next]; ok {
+					continue
+				}
+
+				usedWilds[next] = struct{}{}
+			case s == symbol && !visited[next[0]][next[1]]:
+				visited[next[0]][next[1]] = true
+			default:
+				continue
+			}
+
+			stack = append(stack, next)
+		}
+	}
+
+	for _, rows := range cluster.Indexes {
+		sort.Ints(rows)
+	}
+
+	return cluster
+}
+
+func (cp ClusterPays[Symbol]) isWild(symbol Symbol) bool {
+	return cp.Wild != nil && symbol == *cp.Wild
+}
+
+func neighbours[Symbol comparable](matrix [][]Symbol, reelIndex, rowIndex int) [][2]int {
+	res := make([][2]int, 0, 4)
+
+	if rowIndex > 0 {
+		res = append(res, [2]int{reelIndex, rowIndex - 1})
+	}
+
+	if rowIndex+1 < len(matrix[reelIndex]) {
+		res = append(res, [2]int{reelIndex, rowIndex + 1})
+	}
+
+	if reelIndex > 0 && rowIndex < len(matrix[reelIndex-1]) {
+		res = append(res, [2]int{reelIndex - 1, rowIndex})
+	}
+
+	if reelIndex+1 < len(matrix) && rowIndex < len(matrix[reelIndex+1]) {
+		res = append(res, [2]int{reelIndex + 1, rowIndex})
+	}
+
+	return res
+}
+
+func collectSymbol[Symbol comparable](matrix [][]Symbol, symbol Symbol) *ClusterWin[Symbol] {
+	win := &ClusterWin[Symbol]{Symbol: symbol, Indexes: make([][]int, len(matrix))}
+
+	for reelIndex := range matrix {
+		for rowIndex, s := range matrix[reelIndex] {
+			if s == symbol {
+				win.Indexes[reelIndex] = append(win.Indexes[reelIndex], rowIndex)
+				win.Size++
+			}
+		}
+	}
+
+	return win
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_ClusterPays_CheckWin(t *testing.T) {
+	w := wild
+
+	type testCase struct {
+		name   string
+		matrix [][]int
+		want   []ClusterWin[int]
+	}
+	tests := []testCase{
+		{
+			name: "no clusters",
+			matrix: [][]int{
+				{1, 2, 3},
+				{2, 3, 1},
+				{3, 1, 2},
+			},
+		},
+		{
+			name: "1 cluster",
+			matrix: [][]int{
+				{1, 1, 3},
+				{2, 1, 4},
+				{3, 1, 2},
+			},
+			want: []ClusterWin[int]{
+				{Symbol: 1, Indexes: [][]int{{0, 1}, {1}, {1}}, Size: 4},
+			},
+		},
+		{
+			name: "wild joins 2 clusters",
+			matrix: [][]int{
+				{1, 1, 3},
+				{1, wild, 2},
+				{4, 2, 2},
+			},
+			want: []ClusterWin[int]{
+				{Symbol: 1, Indexes: [][]int{{0, 1}, {0, 1}, nil}, Size: 4},
+				{Symbol: 2, Indexes: [][]int{nil, {1, 2}, {1, 2}}, Size: 4},
+			},
+		},
+		{
+			name: "diagonal is not connected",
+			matrix: [][]int{
+				{1, 2, 1},
+				{2, 1, 2},
+				{1, 2, 1},
+			},
+		},
+		{
+			name: "different reel heights",
+			matrix: [][]int{
+				{1, 1},
+				{2, 1, 1, 1},
+				{1},
+			},
+			want: []ClusterWin[int]{
+				{Symbol: 1, Indexes: [][]int{{0, 1}, {1, 2, 3}, nil}, Size: 5},
+			},
+		},
+		{
+			name: "scatters are not clustered",
+			matrix: [][]int{
+				{scatter, scatter, 3},
+				{scatter, 1, 4},
+				{3, 1, scatter},
+			},
+			want: []ClusterWin[int]{
+				{Symbol: scatter, Indexes: [][]int{{0, 1}, {0}, {2}}, Size: 4},
+			},
+		},
+	}
+
+	cp := NewClusterPays(4, &w, scatter)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cp.CheckWin(tt.matrix)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CheckWin() returned %d wins, want %d", len(got), len(tt.want))
+			}
+
+			for i, win := range got {
+				if win.GetSymbol() != tt.want[i].Symbol || win.Count() != tt.want[i].Size ||
+					!reflect.DeepEqual(win.GetIndexes(), tt.want[i].Indexes) {
+					t.Errorf("CheckWin() = %v, want %v", win, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// fallingWindow shows the first height symbols of the reels from the stops which are not deleted.
+type fallingWindow struct {
+	height  int
+	cp      ClusterPays[int]
+	symbols [][]int
+	abs     [][]int
+}
+
+func (w *fallingWindow) Compute(stops []int, reels []map[int]int, _ []int, deleted []map[int]struct{}) error {
+	w.symbols, w.abs = make([][]int, len(reels)), make([][]int, len(reels))
+
+	for reelIndex, reel := range reels {
+		for i := stops[reelIndex]; len(w.symbols[reelIndex]) < w.height; i++ {
+			if i >= len(reel) {
+				return errors.New("reel is too short")
+			}
+
+			if _, ok := deleted[reelIndex][i]; ok {
+				continue
+			}
+
+			w.symbols[reelIndex] = append(w.symbols[reelIndex], reel[i])
+			w.abs[reelIndex] = append(w.abs[reelIndex], i)
+		}
+	}
+
+	return nil
+}
+
+func (w *fallingWindow) CheckWin() []Win[int] {
+	return w.cp.CheckWin(w.symbols)
+}
+
+func (w *fallingWindow) GetIndexesBySymbol(symbol int) [][]int {
+	return collectSymbol(w.symbols, symbol).Indexes
+}
+
+func (w *fallingWindow) GetAbsIndexesBySymbol(symbol int) [][]int {
+	return w.GetAbsIndexes(w.GetIndexesBySymbol(symbol))
+}
+
+func (w *fallingWindow) GetAbsIndexes(indexes [][]int) [][]int {
+	res := make([][]int, len(indexes))
+
+	for reelIndex, rows := range indexes {
+		for _, row := range rows {
+			res[reelIndex] = append(res[reelIndex], w.abs[reelIndex][row])
+		}
+	}
+
+	return res
+}
+
+func (w *fallingWindow) Matrix() [][]int {
+	return w.symbols
+}
+
+func (w *fallingWindow) GetScatterSymbol() *int    { return nil }
+func (w *fallingWindow) SetScatterQty(int)         {}
+func (w *fallingWindow) GetScatterQty() int        { return 0 }
+func (w *fallingWindow) GetMultiplierSymbol() *int { return nil }
+func (w *fallingWindow) SetMultiplierQty(int)      {}
+func (w *fallingWindow) GetMultiplierQty() int     { return 0 }
+
+func Test_ClusterPays_Spin(t *testing.T) {
+	w := wild
+
+	reels := []map[int]int{
+		{0: 1, 1: 1, 2: 5, 3: 3, 4: 4},
+		{0: wild, 1: 6, 2: 1, 3: 5},
+		{0: 3, 1: 4, 2: 5},
+	}
+
+	window := &fallingWindow{height: 3, cp: NewClusterPays(3, &w)}
+
+	avalanches, award, err := Spin[int](flatAwardGetter{}, window, reels, []int{0, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if award != 3 || len(avalanches) != 2 {
+		t.Fatalf("Spin() award = %d, avalanches = %d, want 3 and 2", award, len(avalanches))
+	}
+
+	if item := avalanches[0].PayItems[0]; item.BaseAward != item.Award || item.Multipliers != nil {
+		t.Errorf("Spin() pay item = %+v, want the base award without multipliers", item)
+	}
+
+	// the cluster is deleted with its wild, the losing 1 on the 2nd reel stays
+	want := [][]int{
+		{5, 3, 4},
+		{6, 1, 5},
+		{3, 4, 5},
+	}
+
+	if !reflect.DeepEqual(avalanches[1].Window, want) {
+		t.Errorf("Spin() 2nd window = %v, want %v", avalanches[1].Window, want)
+	}
+}
@@ -0,0 +1,236 @@
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine/utils"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine/utils/volatility"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	YAMLFormat = "yaml"
+	JSONFormat = "json"
+)
+
+// Definition is a declarative description of a line game math.
+//
+//	symbols:
+//	  - name: A
+//	  - name: W
+//	    wild: true
+//	  - name: S
+//	    scatter: true
+//	window: {width: 5, height: 3}
+//	direction: left-to-right
+//	reels:
+//	  medium: [[A, W, S, A], [A, A, S, W], ...]
+//	paytable:
+//	  A: {3: 5, 4: 20, 5: 100}
+//	pay_lines:
+//	  - [1, 1, 1, 1, 1]
+//	weighted_tables:
+//	  free_spins: {10: 50, 15: 30, 20: 5}
+type Definition struct {
+	Symbols        []SymbolDefinition         `json:"symbols" yaml:"symbols"`
+	Window         WindowDefinition           `json:"window" yaml:"window"`
+	Direction      string                     `json:"direction" yaml:"direction"`
+	Reels          map[string][][]string      `json:"reels" yaml:"reels"` // volatility -> reel -> symbols
+	Paytable       map[string]map[int]int64   `json:"paytable" yaml:"paytable"`
+	PayLines       [][]int                    `json:"pay_lines" yaml:"pay_lines"`
+	WeightedTables map[string]map[int64]int64 `json:"weighted_tables" yaml:"weighted_tables"` // name -> value -> weight
+}
+
+type SymbolDefinition struct {
+	Name    string `json:"name" yaml:"name"`
+	Wild    bool   `json:"wild" yaml:"wild"`
+	Scatter bool   `json:"scatter" yaml:"scatter"`
+}
+
+type WindowDefinition struct {
+	Width  int `json:"width" yaml:"width"`
+	Height int `json:"height" yaml:"height"`
+}
+
+// Load reads the definition from .yml, .yaml or .json file.
+func Load(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := YAMLFormat
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = JSONFormat
+	}
+
+	return Parse(data, format)
+}
+
+// Parse decodes and validates the definition.
+func Parse(data []byte, format string) (*Definition, error) {
+	var def Definition
+
+	switch format {
+	case YAMLFormat:
+		if err := yaml.UnmarshalStrict(data, &def); err != nil {
+			return nil, err
+		}
+	case JSONFormat:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(&def); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown definition format: %s", format)
+	}
+
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &def, nil
+}
+
+// Validate returns all found problems joined in one error.
+func (d *Definition) Validate() error {
+	var errs []error
+
+	symbols := map[string]struct{}{}
+	wilds, scatters := 0, 0
+
+	for _, s := range d.Symbols {
+		if s.Name == "" {
+			errs = append(errs, errors.New("symbol name is empty"))
+
+			continue
+		}
+
+		if _, ok := symbols[s.Name]; ok {
+			errs = append(errs, fmt.Errorf("symbol %s is duplicated", s.Name))
+		}
+
+		symbols[s.Name] = struct{}{}
+
+		if s.Wild {
+			wilds++
+		}
+
+		if s.Scatter {
+			scatters++
+		}
+
+		if s.Wild && s.Scatter {
+			errs = append(errs, fmt.Errorf("symbol %s can not be wild and scatter at the same time", s.Name))
+		}
+	}
+
+	if wilds > 1 || scatters > 1 {
+		errs = append(errs, errors.New("only one wild and one scatter symbol are supported"))
+	}
+
+	if d.Window.Width <= 0 || d.Window.Height <= 0 {
+		errs = append(errs, fmt.Errorf("wrong window size %dx%d", d.Window.Width, d.Window.Height))
+	}
+
+	switch d.Direction {
+	case "", utils.LeftToRightDirection, utils.RightToLeftDirection, utils.BothWaysDirection, utils.AdjacentAnywhereDirection:
+	default:
+		errs = append(errs, fmt.Errorf("unknown direction: %s", d.Direction))
+	}
+
+	if len(d.Reels) == 0 {
+		errs = append(errs, errors.New("reels are empty"))
+	}
+
+	for vol, reels := range d.Reels {
+		if _, err := volatility.VolFromStr(vol); err != nil {
+			errs = append(errs, err)
+		}
+
+		if len(reels) != d.Window.Width {
+			errs = append(errs, fmt.Errorf("reels %s: expect %d reels, got %d", vol, d.Window.Width, len(reels)))
+		}
+
+		for i, reel := range reels {
+			if len(reel) < d.Window.Height {
+				errs = append(errs, fmt.Errorf("reels %s: reel %d is shorter than window height", vol, i))
+			}
+
+			for _, s := range lo.Uniq(reel) {
+				if _, ok := symbols[s]; !ok {
+					errs = append(errs, fmt.Errorf("reels %s: reel %d: unknown symbol %s", vol, i, s))
+				}
+			}
+		}
+	}
+
+	for s, awards := range d.Paytable {
+		if _, ok := symbols[s]; !ok {
+			errs = append(errs, fmt.Errorf("paytable: unknown symbol %s", s))
+		}
+
+		for size, award := range awards {
+			if size <= 0 || size > d.Window.Width || award < 0 {
+				errs = append(errs, fmt.Errorf("paytable: symbol %s: wrong award %d for size %d", s, award, size))
+			}
+		}
+	}
+
+	if len(d.PayLines) == 0 {
+		errs = append(errs, errors.New("pay lines are empty"))
+	}
+
+	for i, payLine := range d.PayLines {
+		if len(payLine) != d.Window.Width {
+			errs = append(errs, fmt.Errorf("pay line %d: expect %d items, got %d", i, d.Window.Width, len(payLine)))
+		}
+
+		for _, row := range payLine {
+			if row < 0 || row >= d.Window.Height {
+				errs = append(errs, fmt.Errorf("pay line %d: row %d is out of window", i, row))
+			}
+		}
+	}
+
+	for name, table := range d.WeightedTables {
+		if len(table) == 0 {
+			errs = append(errs, fmt.Errorf("weighted table %s is empty", name))
+		}
+
+		for value, weight := range table {
+			if weight <= 0 {
+				errs = append(errs, fmt.Errorf("weighted table %s: value %d has non-positive weight", name, value))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *Definition) wild() *string {
+	s, ok := lo.Find(d.Symbols, func(item SymbolDefinition) bool { return item.Wild })
+	if !ok {
+		return nil
+	}
+
+	return &s.Name
+}
+
+func (d *Definition) scatter() *string {
+	s, ok := lo.Find(d.Symbols, func(item SymbolDefinition) bool { return item.Scatter })
+	if !ok {
+		return nil
+	}
+
+	return &s.Name
+}
@@ -0,0 +1,100 @@
+package definition
+
+import (
+	"strings"
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine/utils/volatility"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"github.com/stretchr/testify/require"
+)
+
+const testDefinition = `
+symbols:
+  - name: A
+  - name: K
+  - name: W
+    wild: true
+  - name: S
+    scatter: true
+window: {width: 3, height: 2}
+reels:
+  medium:
+    - [A, K, S]
+    - [W, K, A]
+    - [A, S, K]
+paytable:
+  A: {3: 10}
+  K: {3: 5}
+pay_lines:
+  - [0, 0, 0]
+  - [1, 1, 1]
+weighted_tables:
+  free_spins: {10: 3, 15: 1}
+`
+
+func TestDefinition_Build(t *testing.T) {
+	client, err := rng.NewMockClient(nil)
+	require.NoError(t, err)
+
+	def, err := Parse([]byte(testDefinition), YAMLFormat)
+	require.NoError(t, err)
+
+	game, err := def.Build(client)
+	require.NoError(t, err)
+	require.Equal(t, "W", *game.Wild)
+	require.Equal(t, "S", *game.Scatter)
+
+	window, err := game.NewWindow(volatility.MediumType, []int{0, 0, 1})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"A", "K"}, {"W", "K"}, {"S", "K"}}, window.Matrix())
+
+	payLines := game.CalcPayLines(window)
+	require.Len(t, payLines, 1)
+	require.Equal(t, "K", payLines[0].PaySymbol)
+	require.Equal(t, int64(5), payLines[0].Award)
+
+	chooser, err := game.Table("free_spins")
+	require.NoError(t, err)
+
+	value, err := chooser.Pick()
+	require.NoError(t, err)
+	require.Contains(t, []int64{10, 15}, value)
+
+	_, _, err = game.Spin(volatility.MediumType)
+	require.NoError(t, err)
+}
+
+func TestGame_NewWindow(t *testing.T) {
+	client, err := rng.NewMockClient(nil)
+	require.NoError(t, err)
+
+	def, err := Parse([]byte(testDefinition), YAMLFormat)
+	require.NoError(t, err)
+
+	game, err := def.Build(client)
+	require.NoError(t, err)
+
+	_, err = game.NewWindow(volatility.MediumType, []int{0, -1, 0})
+	require.ErrorContains(t, err, "stop -1 of reel 1 is out of range")
+
+	_, err = game.NewWindow(volatility.MediumType, []int{0, 0, 3})
+	require.ErrorContains(t, err, "stop 3 of reel 2 is out of range")
+
+	_, err = game.NewWindow(volatility.MediumType, []int{0, 0})
+	require.ErrorContains(t, err, "expect 3 stops, got 2")
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	broken := strings.Replace(testDefinition, "[A, S, K]", "[A, X]", 1)
+	broken = strings.Replace(broken, "[1, 1, 1]", "[1, 2, 1]", 1)
+
+	_, err := Parse([]byte(broken), YAMLFormat)
+	require.ErrorContains(t, err, "unknown symbol X")
+	require.ErrorContains(t, err, "pay line 1: row 2 is out of window")
+}
+
+func TestParse_JSONUnknownFields(t *testing.T) {
+	_, err := Parse([]byte(`{"symbols": [{"name": "A"}], "paylines": [[0]]}`), JSONFormat)
+	require.ErrorContains(t, err, `unknown field "paylines"`)
+}
@@ -0,0 +1,170 @@
+package definition
+
+import (
+	"fmt"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine/utils"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine/utils/volatility"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	baseUtils "bitbucket.org/play-workspace/base-slot-server/utils"
+)
+
+// Game is a ready-to-use line game built from the Definition.
+type Game struct {
+	Wild      *string
+	Scatter   *string
+	Direction string
+
+	Paytable Paytable
+	PayLines [][]int
+	Reels    map[volatility.Type][][]string
+	Tables   map[string]*baseUtils.Chooser[int64, int64]
+
+	height int
+	rand   rng.Client
+}
+
+// Build validates the definition and creates choosers for weighted tables.
+func (d *Definition) Build(rand rng.Client) (*Game, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := &Game{
+		Wild:      d.wild(),
+		Scatter:   d.scatter(),
+		Direction: d.Direction,
+
+		Paytable: d.Paytable,
+		PayLines: d.PayLines,
+		Reels:    map[volatility.Type][][]string{},
+		Tables:   map[string]*baseUtils.Chooser[int64, int64]{},
+
+		height: d.Window.Height,
+		rand:   rand,
+	}
+
+	if g.Direction == "" {
+		g.Direction = utils.LeftToRightDirection
+	}
+
+	for vol, reels := range d.Reels {
+		g.Reels[volatility.Type(vol)] = reels
+	}
+
+	for name, table := range d.WeightedTables {
+		chooser, err := baseUtils.NewChooserFromMap(rand, table)
+		if err != nil {
+			return nil, fmt.Errorf("weighted table %s: %w", name, err)
+		}
+
+		g.Tables[name] = chooser
+	}
+
+	return g, nil
+}
+
+// Spin generates random stops on the reels of the volatility and returns the window.
+func (g *Game) Spin(vol volatility.Type) (*Window, []int, error) {
+	reels, ok := g.Reels[vol]
+	if !ok {
+		return nil, nil, fmt.Errorf("no reels for volatility %s", vol)
+	}
+
+	lengths := make([]uint64, len(reels))
+	for i, reel := range reels {
+		lengths[i] = uint64(len(reel))
+	}
+
+	rands, err := g.rand.RandSlice(lengths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stops := make([]int, len(rands))
+	for i, r := range rands {
+		stops[i] = int(r)
+	}
+
+	window, err := g.NewWindow(vol, stops)
+
+	return window, stops, err
+}
+
+// NewWindow builds the window from the stops, it is used for restoring and cheats.
+func (g *Game) NewWindow(vol volatility.Type, stops []int) (*Window, error) {
+	reels, ok := g.Reels[vol]
+	if !ok {
+		return nil, fmt.Errorf("no reels for volatility %s", vol)
+	}
+
+	if len(stops) != len(reels) {
+		return nil, fmt.Errorf("expect %d stops, got %d", len(reels), len(stops))
+	}
+
+	w := make(Window, len(reels))
+
+	for i, reel := range reels {
+		if stops[i] < 0 || stops[i] >= len(reel) {
+			return nil, fmt.Errorf("stop %d of reel %d is out of range [0, %d)", stops[i], i, len(reel))
+		}
+
+		w[i] = make([]string, g.height)
+
+		for j := 0; j < g.height; j++ {
+			w[i][j] = reel[(stops[i]+j)%len(reel)]
+		}
+	}
+
+	return &w, nil
+}
+
+// CalcPayLines evaluates all pay lines of the game on the window.
+func (g *Game) CalcPayLines(window *Window) []utils.PayLine[int, string] {
+	return utils.CalcBasePayLines[int, string](g.PayLines, window, g.Paytable, g.Scatter, g.Wild, g.Direction)
+}
+
+// Table returns the chooser of the weighted table by name.
+func (g *Game) Table(name string) (*baseUtils.Chooser[int64, int64], error) {
+	chooser, ok := g.Tables[name]
+	if !ok {
+		return nil, fmt.Errorf("weighted table %s not found", name)
+	}
+
+	return chooser, nil
+}
+
+// Paytable implements utils.AwardGetter: symbol -> size -> award.
+type Paytable map[string]map[int]int64
+
+func (p Paytable) GetAward(symbol string, size int) int64 {
+	return p[symbol][size]
+}
+
+// Window implements utils.Window, the pay item is the row index.
+type Window [][]string // reel -> rows
+
+func (w *Window) GetSymbol(reelIndex int, payItem int) string {
+	return (*w)[reelIndex][payItem]
+}
+
+func (w *Window) GetByIndexes(reelIndex, symbolIndex int) (int, string) {
+	return symbolIndex, (*w)[reelIndex][symbolIndex]
+}
+
+func (w *Window) GetHeight() int {
+	if len(*w) == 0 {
+		return 0
+	}
+
+	return len((*w)[0])
+}
+
+func (w *Window) GetWidth() int {
+	return len(*w)
+}
+
+// Matrix returns 2D matrix of window for the frontend.
+func (w *Window) Matrix() [][]string {
+	return *w
+}
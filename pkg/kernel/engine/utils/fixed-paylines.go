@@ -3,6 +3,11 @@ package utils
 const (
 	LeftToRightDirection = "left-to-right"
 	RightToLeftDirection = "right-to-left"
+
+	// BothWaysDirection evaluates left-to-right and right-to-left, full line wins are paid once.
+	BothWaysDirection = "both-ways"
+	// AdjacentAnywhereDirection pays the best run of adjacent symbols starting on any reel.
+	AdjacentAnywhereDirection = "adjacent-anywhere"
 )
 
 type AwardGetter[Symbol comparable] interface {
@@ -24,30 +29,127 @@ func (st ScatterTrigger[PayItem]) Count() int {
 
 // CalcBasePayLines scatter and wild are nullable
 func CalcBasePayLines[PayItem, Symbol comparable](payLines [][]PayItem, window Window[PayItem, Symbol], ag AwardGetter[Symbol], scatter, wild *Symbol, direction string) []PayLine[PayItem, Symbol] {
+	return CalcBasePayLinesWithMultipliers(payLines, window, ag, scatter, wild, direction, Multipliers[PayItem, Symbol]{})
+}
+
+// CalcBasePayLinesWithMultipliers is CalcBasePayLines with multiplied awards, the multipliers are recorded in PayLine.
+func CalcBasePayLinesWithMultipliers[PayItem, Symbol comparable](payLines [][]PayItem, window Window[PayItem, Symbol], ag AwardGetter[Symbol], scatter, wild *Symbol, direction string, multipliers Multipliers[PayItem, Symbol]) []PayLine[PayItem, Symbol] {
 	var foundPayLines []PayLine[PayItem, Symbol]
 
 	for payLineIndex, payLine := range payLines {
-		symbol, payLineItems, resDirection := SwitchPayLine(payLine, window, wild, direction)
-
-		if scatter != nil && symbol == *scatter {
-			continue
-		}
+		for _, match := range matchPayLine(payLine, window, ag, scatter, wild, direction) {
+			award := ag.GetAward(match.symbol, len(match.payLineItems))
 
-		if award := ag.GetAward(symbol, len(payLineItems)); award > 0 {
-			foundPayLines = append(foundPayLines, PayLine[PayItem, Symbol]{
+			pl := PayLine[PayItem, Symbol]{
 				PayLineIndex: payLineIndex,
-				PayLineItems: payLineItems,
-				PaySymbol:    symbol,
+				PayLineItems: match.payLineItems,
+				PaySymbol:    match.symbol,
 				Award:        award,
+				BaseAward:    award,
 
-				Direction: resDirection,
-			})
+				Direction: match.direction,
+				Offset:    match.offset,
+			}
+
+			reelIndexes, symbols := payLineSymbols(payLine, pl.PayLineItems, window, pl.Direction, pl.Offset)
+
+			if m := multipliers.compute(reelIndexes, pl.PayLineItems, symbols, 0); m != nil {
+				pl.Multipliers = m
+				pl.Award = award * m.Total
+			}
+
+			foundPayLines = append(foundPayLines, pl)
 		}
 	}
 
 	return foundPayLines
 }
 
+type payLineMatch[PayItem, Symbol comparable] struct {
+	symbol       Symbol
+	payLineItems []PayItem
+	direction    string
+	offset       int
+}
+
+// matchPayLine returns paid matches of the pay line according to the direction rule.
+func matchPayLine[PayItem, Symbol comparable](payLine []PayItem, window Window[PayItem, Symbol], ag AwardGetter[Symbol], scatter, wild *Symbol, direction string) []payLineMatch[PayItem, Symbol] {
+	var matches []payLineMatch[PayItem, Symbol]
+
+	paid := func(m payLineMatch[PayItem, Symbol]) bool {
+		if scatter != nil && m.symbol == *scatter {
+			return false
+		}
+
+		return ag.GetAward(m.symbol, len(m.payLineItems)) > 0
+	}
+
+	switch direction {
+	case BothWaysDirection:
+		symbol, payLineItems := CheckPayLine(payLine, window, wild, LeftToRightDirection)
+		ltr := payLineMatch[PayItem, Symbol]{symbol: symbol, payLineItems: payLineItems, direction: LeftToRightDirection}
+
+		if paid(ltr) {
+			matches = append(matches, ltr)
+		}
+
+		if len(payLineItems) == len(payLine) {
+			return matches
+		}
+
+		symbol, payLineItems = CheckPayLine(payLine, window, wild, RightToLeftDirection)
+		rtl := payLineMatch[PayItem, Symbol]{symbol: symbol, payLineItems: payLineItems, direction: RightToLeftDirection}
+
+		if paid(rtl) {
+			matches = append(matches, rtl)
+		}
+	case AdjacentAnywhereDirection:
+		var (
+			best      payLineMatch[PayItem, Symbol]
+			bestAward int64
+		)
+
+		for offset := 0; offset < len(payLine); offset++ {
+			symbol, payLineItems := CheckPayLineFrom(payLine, window, wild, AdjacentAnywhereDirection, offset)
+			m := payLineMatch[PayItem, Symbol]{symbol: symbol, payLineItems: payLineItems, direction: AdjacentAnywhereDirection, offset: offset}
+
+			if !paid(m) {
+				continue
+			}
+
+			if award := ag.GetAward(symbol, len(payLineItems)); award > bestAward {
+				best, bestAward = m, award
+			}
+		}
+
+		if bestAward > 0 {
+			matches = append(matches, best)
+		}
+	default:
+		symbol, payLineItems, resDirection := SwitchPayLine(payLine, window, wild, direction)
+		m := payLineMatch[PayItem, Symbol]{symbol: symbol, payLineItems: payLineItems, direction: resDirection}
+
+		if paid(m) {
+			matches = append(matches, m)
+		}
+	}
+
+	return matches
+}
+
+// payLineSymbols returns reel indexes and symbols of the pay line items found by CheckPayLine.
+func payLineSymbols[PayItem, Symbol comparable](payLine, payLineItems []PayItem, window Window[PayItem, Symbol], direction string, offset int) ([]int, []Symbol) {
+	reelIndexes := make([]int, 0, len(payLineItems))
+	symbols := make([]Symbol, 0, len(payLineItems))
+
+	for it := NewPayLineIteratorFrom(payLine, direction, offset); it.Valid() && len(reelIndexes) < len(payLineItems); it.Next() {
+		reelIndexes = append(reelIndexes, it.Index())
+		symbols = append(symbols, window.GetSymbol(it.Index(), it.Value()))
+	}
+
+	return reelIndexes, symbols
+}
+
 func CalcScatter[PayItem, Symbol comparable](window Window[PayItem, Symbol], scatter Symbol) ScatterTrigger[PayItem] {
 	var scatterPayLine ScatterTrigger[PayItem]
 	w, h := window.GetWidth(), window.GetHeight()
@@ -97,7 +199,12 @@ func SwitchPayLine[PayItem, Symbol comparable](payLine []PayItem, window Window[
 }
 
 func CheckPayLine[PayItem, Symbol comparable](payLine []PayItem, window Window[PayItem, Symbol], wild *Symbol, direction string) (symbol Symbol, payLineItems []PayItem) {
-	for it := NewPayLineIterator(payLine, direction); it.Valid(); it.Next() {
+	return CheckPayLineFrom(payLine, window, wild, direction, 0)
+}
+
+// CheckPayLineFrom is CheckPayLine that starts from the offset item of the pay line in the direction.
+func CheckPayLineFrom[PayItem, Symbol comparable](payLine []PayItem, window Window[PayItem, Symbol], wild *Symbol, direction string, offset int) (symbol Symbol, payLineItems []PayItem) {
+	for it := NewPayLineIteratorFrom(payLine, direction, offset); it.Valid(); it.Next() {
 		reelIndex, payItem := it.Index(), it.Value()
 
 		s := window.GetSymbol(reelIndex, payItem)
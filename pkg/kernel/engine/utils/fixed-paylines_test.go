@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_CalcBasePayLines_Directions(t *testing.T) {
+	wildSymbol := wild
+
+	type testCase struct {
+		name      string
+		window    lineWindow
+		direction string
+		want      []PayLine[int, int]
+	}
+	tests := []testCase{
+		{
+			name:      "both ways pays each direction",
+			window:    lineWindow{{1}, {1}, {1}, {2}, {2}, {2}},
+			direction: BothWaysDirection,
+			want: []PayLine[int, int]{
+				{PayLineItems: []int{0, 0, 0}, PaySymbol: 1, Direction: LeftToRightDirection, Award: 3, BaseAward: 3},
+				{PayLineItems: []int{0, 0, 0}, PaySymbol: 2, Direction: RightToLeftDirection, Award: 6, BaseAward: 6},
+			},
+		},
+		{
+			name:      "both ways pays full line once",
+			window:    lineWindow{{1}, {wild}, {1}, {1}, {1}},
+			direction: BothWaysDirection,
+			want: []PayLine[int, int]{
+				{PayLineItems: []int{0, 0, 0, 0, 0}, PaySymbol: 1, Direction: LeftToRightDirection, Award: 5, BaseAward: 5},
+			},
+		},
+		{
+			name:      "adjacent anywhere pays the best run",
+			window:    lineWindow{{1}, {1}, {1}, {3}, {3}, {3}, {3}},
+			direction: AdjacentAnywhereDirection,
+			want: []PayLine[int, int]{
+				{PayLineItems: []int{0, 0, 0, 0}, PaySymbol: 3, Direction: AdjacentAnywhereDirection, Offset: 3, Award: 12, BaseAward: 12},
+			},
+		},
+		{
+			name:      "adjacent anywhere in the middle",
+			window:    lineWindow{{1}, {2}, {2}, {wild}, {4}},
+			direction: AdjacentAnywhereDirection,
+			want: []PayLine[int, int]{
+				{PayLineItems: []int{0, 0, 0}, PaySymbol: 2, Direction: AdjacentAnywhereDirection, Offset: 1, Award: 6, BaseAward: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payLine := make([]int, len(tt.window))
+
+			got := CalcBasePayLines([][]int{payLine}, Window[int, int](tt.window), flatAwardGetter{}, nil, &wildSymbol, tt.direction)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CalcBasePayLines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -100,3 +100,88 @@ func isFirstSubSlice(cors1, cors2 []int) bool {
 
 	return true
 }
+
+// CheckWays is a ways-to-win alternative for CheckWindow, it returns one win per symbol instead of one win per path.
+// Ways are counted multiplicatively per reel, so the complexity does not depend on the number of ways.
+// The award for the win is GetAward(symbol, Length) * Ways.
+// A win needs at least one symbol on the counted reels, the reels of wilds only do not pay.
+func CheckWays[Symbol comparable](window MegaWaysWindow[Symbol], wildSymbol Symbol, scatterSymbol *Symbol) []Win[Symbol] {
+	var (
+		wins    []Win[Symbol]
+		symbols []Symbol
+		seen    = map[Symbol]struct{}{}
+	)
+
+	for i := 0; i < window.GetWidth(); i++ {
+		for j := 0; j < window.GetHeight(i); j++ {
+			s := window.GetSymbol(i, j)
+
+			// wilds are counted in the ways of the other symbols, they do not form a separate win
+			if _, ok := seen[s]; ok || s == wildSymbol || (scatterSymbol != nil && s == *scatterSymbol) {
+				continue
+			}
+
+			seen[s] = struct{}{}
+			symbols = append(symbols, s)
+		}
+	}
+
+	for _, symbol := range symbols {
+		if win := countWays(window, symbol, wildSymbol); win.Length > 0 {
+			wins = append(wins, win)
+		}
+	}
+
+	if scatterSymbol != nil {
+		win := &WaysWin[Symbol]{Symbol: *scatterSymbol, Ways: 1}
+
+		for i := 0; i < window.GetWidth(); i++ {
+			var rows []int
+
+			for j := 0; j < window.GetHeight(i); j++ {
+				if window.GetSymbol(i, j) == *scatterSymbol {
+					rows = append(rows, j)
+				}
+			}
+
+			win.Positions = append(win.Positions, rows)
+			win.Length += len(rows)
+		}
+
+		if win.Length > 0 {
+			wins = append(wins, win)
+		}
+	}
+
+	return wins
+}
+
+func countWays[Symbol comparable](window MegaWaysWindow[Symbol], symbol, wild Symbol) *WaysWin[Symbol] {
+	win := &WaysWin[Symbol]{Symbol: symbol, Ways: 1}
+	found := false
+
+	for i := 0; i < window.GetWidth(); i++ {
+		var rows []int
+
+		for j := 0; j < window.GetHeight(i); j++ {
+			if s := window.GetSymbol(i, j); s == symbol || s == wild {
+				rows = append(rows, j)
+				found = found || s == symbol
+			}
+		}
+
+		if len(rows) == 0 {
+			break
+		}
+
+		win.Positions = append(win.Positions, rows)
+		win.Ways *= len(rows)
+		win.Length++
+	}
+
+	if !found {
+		return &WaysWin[Symbol]{Symbol: symbol}
+	}
+
+	return win
+}
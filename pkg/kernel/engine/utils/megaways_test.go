@@ -167,3 +167,102 @@ func Test_CheckWindow(t *testing.T) {
 		})
 	}
 }
+
+func Test_CheckWays(t *testing.T) {
+	type testCase struct {
+		name   string
+		window MegaWaysWindow[int]
+		want   []WaysWin[int]
+	}
+	tests := []testCase{
+		{
+			name: "1 symbol 2 ways",
+			window: window{
+				{1},
+				{1, 1},
+				{1},
+			},
+			want: []WaysWin[int]{
+				{Symbol: 1, Ways: 2, Length: 3, Positions: [][]int{{0}, {0, 1}, {0}}},
+			},
+		},
+		{
+			name: "2 symbols with wild",
+			window: window{
+				{1, 2},
+				{1, wild},
+				{1},
+			},
+			want: []WaysWin[int]{
+				{Symbol: 1, Ways: 2, Length: 3, Positions: [][]int{{0}, {0, 1}, {0}}},
+				{Symbol: 2, Ways: 1, Length: 2, Positions: [][]int{{1}, {1}}},
+			},
+		},
+		{
+			name: "wilds do not pay separately",
+			window: window{
+				{wild},
+				{wild},
+				{1},
+			},
+			want: []WaysWin[int]{
+				{Symbol: 1, Ways: 1, Length: 3, Positions: [][]int{{0}, {0}, {0}}},
+			},
+		},
+		{
+			name: "wild reels do not pay symbols outside the ways",
+			window: window{
+				{wild},
+				{wild},
+				{1},
+				{2},
+			},
+			want: []WaysWin[int]{
+				{Symbol: 1, Ways: 1, Length: 3, Positions: [][]int{{0}, {0}, {0}}},
+			},
+		},
+		{
+			name: "scatters",
+			window: window{
+				{0, 0, 0, scatter},
+				{1, 1},
+				{2, scatter},
+				{3, scatter, scatter},
+			},
+			want: []WaysWin[int]{
+				{Symbol: 0, Ways: 3, Length: 1, Positions: [][]int{{0, 1, 2}}},
+				{Symbol: scatter, Ways: 1, Length: 4, Positions: [][]int{{3}, nil, {1}, {1, 2}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckWays(tt.window, wild, &scatter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CheckWays() returned %d wins, want %d", len(got), len(tt.want))
+			}
+
+			for i, w := range got {
+				if !reflect.DeepEqual(w, &tt.want[i]) {
+					t.Errorf("CheckWays() = %v, want %v", w, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_CheckWays_117649(t *testing.T) {
+	w := make(window, 6)
+	for i := range w {
+		w[i] = []int{1, 1, 1, 1, 1, 1, wild}
+	}
+
+	got := CheckWays(w, wild, &scatter)
+	if len(got) != 1 {
+		t.Fatalf("CheckWays() returned %d wins, want 1", len(got))
+	}
+
+	if ways := got[0].(*WaysWin[int]).Ways; ways != 117649 || got[0].Count() != 6 {
+		t.Errorf("CheckWays() = %v ways, want 117649", ways)
+	}
+}
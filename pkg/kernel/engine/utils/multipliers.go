@@ -0,0 +1,104 @@
+package utils
+
+const (
+	MultiplyMode = "multiply"
+	SumMode      = "sum"
+)
+
+// Multipliers describes multiplier sources applied on top of AwardGetter.GetAward.
+// All sources are nullable, zero value means the win is not multiplied.
+type Multipliers[PayItem, Symbol comparable] struct {
+	// Wild returns the multiplier of the wild symbol in the win, 0 or 1 for symbols without multiplier.
+	Wild func(symbol Symbol) int64
+	// Position returns the multiplier of the cell in the win, 0 or 1 for cells without multiplier.
+	Position func(reelIndex int, payItem PayItem) int64
+	// Global is applied to every win, for example, free spins multiplier.
+	Global int64
+	// Steps is a global progressive multiplier per avalanche step, the last value is used for all next steps.
+	Steps []int64
+
+	// Mode is the way to combine multipliers of the same source in one win: MultiplyMode (default) or SumMode.
+	Mode string
+}
+
+// MultiplierBreakdown is the multipliers applied to the win, it is used by the frontend to animate them.
+type MultiplierBreakdown struct {
+	Wild     int64 `json:"wild"`
+	Position int64 `json:"position"`
+	Global   int64 `json:"global"`
+	Step     int64 `json:"step"`
+	Total    int64 `json:"total"`
+}
+
+func (m Multipliers[PayItem, Symbol]) step(step int) int64 {
+	if len(m.Steps) == 0 {
+		return 1
+	}
+
+	if step >= len(m.Steps) {
+		return orOne(m.Steps[len(m.Steps)-1])
+	}
+
+	return orOne(m.Steps[step])
+}
+
+// compute returns nil if there is no multiplier for the win.
+// reelIndexes[i] is the reel index of payItems[i].
+func (m Multipliers[PayItem, Symbol]) compute(reelIndexes []int, payItems []PayItem, symbols []Symbol, step int) *MultiplierBreakdown {
+	var wilds, positions []int64
+
+	for i := range payItems {
+		if m.Wild != nil {
+			wilds = append(wilds, m.Wild(symbols[i]))
+		}
+
+		if m.Position != nil {
+			positions = append(positions, m.Position(reelIndexes[i], payItems[i]))
+		}
+	}
+
+	breakdown := &MultiplierBreakdown{
+		Wild:     m.combine(wilds),
+		Position: m.combine(positions),
+		Global:   orOne(m.Global),
+		Step:     m.step(step),
+	}
+
+	breakdown.Total = breakdown.Wild * breakdown.Position * breakdown.Global * breakdown.Step
+
+	if breakdown.Total == 1 {
+		return nil
+	}
+
+	return breakdown
+}
+
+func (m Multipliers[PayItem, Symbol]) combine(values []int64) int64 {
+	if m.Mode == SumMode {
+		var res int64
+
+		for _, v := range values {
+			if v > 1 {
+				res += v
+			}
+		}
+
+		return orOne(res)
+	}
+
+	var res int64 = 1
+
+	for _, v := range values {
+		res *= orOne(v)
+	}
+
+	return res
+}
+
+func orOne(v int64) int64 {
+	if v <= 0 {
+		return 1
+	}
+
+	return v
+}
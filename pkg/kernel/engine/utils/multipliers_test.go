@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+const wildX2 = 9
+
+type lineWindow [][]int // reel -> rows
+
+func (w lineWindow) GetSymbol(reelIndex int, row int) int {
+	return w[reelIndex][row]
+}
+
+func (w lineWindow) GetByIndexes(reelIndex, symbolIndex int) (int, int) {
+	return symbolIndex, w[reelIndex][symbolIndex]
+}
+
+func (w lineWindow) GetHeight() int {
+	return len(w[0])
+}
+
+func (w lineWindow) GetWidth() int {
+	return len(w)
+}
+
+type flatAwardGetter struct{}
+
+func (flatAwardGetter) GetAward(symbol int, size int) int64 {
+	if size < 3 {
+		return 0
+	}
+
+	return int64(symbol * size)
+}
+
+func Test_CalcBasePayLinesWithMultipliers(t *testing.T) {
+	w := lineWindow{
+		{1, 2},
+		{wildX2, 2},
+		{1, 2},
+	}
+	wildSymbol := wildX2
+
+	multipliers := Multipliers[int, int]{
+		Wild: func(symbol int) int64 {
+			if symbol == wildX2 {
+				return 2
+			}
+
+			return 1
+		},
+		Position: func(reelIndex int, row int) int64 {
+			if reelIndex == 2 && row == 1 {
+				return 5
+			}
+
+			return 1
+		},
+		Global: 3,
+	}
+
+	got := CalcBasePayLinesWithMultipliers([][]int{{0, 0, 0}, {1, 1, 1}}, Window[int, int](w), flatAwardGetter{}, nil, &wildSymbol, LeftToRightDirection, multipliers)
+
+	want := []PayLine[int, int]{
+		{
+			PayLineIndex: 0, PayLineItems: []int{0, 0, 0}, PaySymbol: 1, Direction: LeftToRightDirection,
+			Award: 18, BaseAward: 3, Multipliers: &MultiplierBreakdown{Wild: 2, Position: 1, Global: 3, Step: 1, Total: 6},
+		},
+		{
+			PayLineIndex: 1, PayLineItems: []int{1, 1, 1}, PaySymbol: 2, Direction: LeftToRightDirection,
+			Award: 90, BaseAward: 6, Multipliers: &MultiplierBreakdown{Wild: 1, Position: 5, Global: 3, Step: 1, Total: 15},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CalcBasePayLinesWithMultipliers() = %+v, want %+v", got, want)
+	}
+}
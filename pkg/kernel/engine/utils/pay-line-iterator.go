@@ -5,18 +5,27 @@ import "go.uber.org/zap"
 type PayLineIterator[PayItem comparable] struct {
 	PayLine   []PayItem
 	direction string
+	offset    int
 
 	i int
 }
 
 func NewPayLineIterator[PayItem comparable](payLine []PayItem, direction string) PayLineIterator[PayItem] {
-	it := PayLineIterator[PayItem]{PayLine: payLine}
+	return NewPayLineIteratorFrom(payLine, direction, 0)
+}
+
+// NewPayLineIteratorFrom skips first offset items of the pay line in the direction.
+// It is used for adjacent-anywhere wins that can start on any reel.
+func NewPayLineIteratorFrom[PayItem comparable](payLine []PayItem, direction string, offset int) PayLineIterator[PayItem] {
+	it := PayLineIterator[PayItem]{PayLine: payLine, offset: offset}
 
 	switch direction {
 	case LeftToRightDirection:
 		it.direction = LeftToRightDirection
 	case RightToLeftDirection:
 		it.direction = RightToLeftDirection
+	case AdjacentAnywhereDirection:
+		it.direction = AdjacentAnywhereDirection
 	default:
 		{
 			zap.S().Warn("wrong direction")
@@ -31,10 +40,10 @@ func NewPayLineIterator[PayItem comparable](payLine []PayItem, direction string)
 
 func (it *PayLineIterator[PayItem]) Init() *PayLineIterator[PayItem] {
 	switch it.direction {
-	case LeftToRightDirection:
-		it.i = 0
+	case LeftToRightDirection, AdjacentAnywhereDirection:
+		it.i = it.offset
 	case RightToLeftDirection:
-		it.i = len(it.PayLine) - 1
+		it.i = len(it.PayLine) - 1 - it.offset
 	}
 
 	return it
@@ -42,7 +51,7 @@ func (it *PayLineIterator[PayItem]) Init() *PayLineIterator[PayItem] {
 
 func (it *PayLineIterator[PayItem]) Next() {
 	switch it.direction {
-	case LeftToRightDirection:
+	case LeftToRightDirection, AdjacentAnywhereDirection:
 		it.i++
 	case RightToLeftDirection:
 		it.i--
@@ -51,7 +60,7 @@ func (it *PayLineIterator[PayItem]) Next() {
 
 func (it *PayLineIterator[PayItem]) Valid() bool {
 	switch it.direction {
-	case LeftToRightDirection:
+	case LeftToRightDirection, AdjacentAnywhereDirection:
 		return it.i < len(it.PayLine)
 	case RightToLeftDirection:
 		return it.i >= 0
@@ -67,3 +76,8 @@ func (it *PayLineIterator[PayItem]) Value() PayItem {
 func (it *PayLineIterator[PayItem]) Index() int {
 	return it.i
 }
+
+// Direction returns the rule of the iteration: left-to-right, right-to-left or adjacent-anywhere.
+func (it *PayLineIterator[PayItem]) Direction() string {
+	return it.direction
+}
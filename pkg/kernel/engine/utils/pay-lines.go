@@ -5,9 +5,12 @@ type PayLine[PayItem, Symbol comparable] struct {
 	PayLineItems []PayItem
 	PaySymbol    Symbol
 
-	Direction string
+	Direction string // rule that produced the win: left-to-right, right-to-left or adjacent-anywhere
+	Offset    int    // index of the first pay line item for adjacent-anywhere wins
 
-	Award int64
+	Award       int64
+	BaseAward   int64                // award before multipliers
+	Multipliers *MultiplierBreakdown // nil if the award is not multiplied
 }
 
 type MegaWayWin[Symbol comparable] struct {
@@ -30,3 +33,27 @@ func (p *MegaWayWin[Symbol]) GetIndexes() [][]int {
 func (p *MegaWayWin[Symbol]) Count() int {
 	return len(p.Path)
 }
+
+// WaysWin is a ways-to-win combination: Ways paths of Length reels for the Symbol.
+type WaysWin[Symbol comparable] struct {
+	Symbol    Symbol
+	Ways      int
+	Length    int
+	Positions [][]int // reel -> rows
+}
+
+func (w *WaysWin[Symbol]) GetSymbol() Symbol {
+	return w.Symbol
+}
+
+func (w *WaysWin[Symbol]) GetIndexes() [][]int {
+	return w.Positions
+}
+
+func (w *WaysWin[Symbol]) Count() int {
+	return w.Length
+}
+
+func (w *WaysWin[Symbol]) GetWays() int {
+	return w.Ways
+}
@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	baseUtils "bitbucket.org/play-workspace/base-slot-server/utils"
+	"github.com/samber/lo"
+)
+
+var ErrRespinsFinished = errors.New("respins are finished")
+
+// CoinValue is a value of the landed coin, Jackpot is not empty for jackpot coins.
+type CoinValue struct {
+	Value   int64  `json:"value"` // wager multiplier, divided by HoldAndWinConfig.Divider
+	Jackpot string `json:"jackpot,omitempty"`
+}
+
+type Coin struct {
+	Position Position  `json:"position"`
+	Value    CoinValue `json:"value"`
+}
+
+type RespinStep struct {
+	Coins []Coin `json:"coins"` // coins landed on the step
+	Left  int    `json:"left"`  // respins left after the step
+}
+
+// HoldAndWinConfig describes a "respin until no new coins land" feature.
+type HoldAndWinConfig struct {
+	Width  int
+	Height int
+
+	// Respins is the number of respins, it is reset every time a new coin lands.
+	Respins int
+
+	// Land decides if a coin lands on the empty cell.
+	Land *baseUtils.Chooser[bool, int64]
+	// Coins picks the value of the landed coin.
+	Coins *baseUtils.Chooser[CoinValue, int64]
+
+	// Jackpots is jackpot tier -> wager multiplier.
+	Jackpots map[string]int64
+	// FullGridJackpot is awarded when all the cells are locked, empty if there is no such jackpot.
+	FullGridJackpot string
+
+	// Divider for coin values and jackpots, allows to use fractional multipliers (0 means 1).
+	Divider int64
+}
+
+// HoldAndWin is the state of the feature, keep it in the spin to serialize it. Every respin is recorded
+// in Steps, so the frontend can replay them one by one and RespinRestoringIndexes can track the shown ones.
+type HoldAndWin struct {
+	Locked  []Coin       `json:"locked"`
+	Steps   []RespinStep `json:"steps"`
+	Left    int          `json:"left"`
+	Jackpot string       `json:"jackpot,omitempty"` // full grid jackpot
+}
+
+// NewHoldAndWin starts the feature with the coins that triggered it, the coins must be on different cells of the grid.
+func NewHoldAndWin(cfg HoldAndWinConfig, trigger []Coin) (*HoldAndWin, error) {
+	seen := make(map[Position]struct{}, len(trigger))
+
+	for _, coin := range trigger {
+		p := coin.Position
+		if p.Reel < 0 || p.Reel >= cfg.Width || p.Row < 0 || p.Row >= cfg.Height {
+			return nil, fmt.Errorf("trigger coin %v is out of grid %dx%d", p, cfg.Width, cfg.Height)
+		}
+
+		if _, ok := seen[p]; ok {
+			return nil, fmt.Errorf("duplicated trigger coin %v", p)
+		}
+
+		seen[p] = struct{}{}
+	}
+
+	h := &HoldAndWin{Locked: trigger, Left: cfg.Respins}
+	h.checkFullGrid(cfg)
+
+	return h, nil
+}
+
+// Active returns true if there are respins left, the feature is finished when the grid is full.
+func (h *HoldAndWin) Active() bool {
+	return h.Left > 0 && h.Jackpot == ""
+}
+
+// Respin plays a single respin: coins land on the empty cells and stay locked till the end of the feature.
+func (h *HoldAndWin) Respin(cfg HoldAndWinConfig) error {
+	if !h.Active() {
+		return ErrRespinsFinished
+	}
+
+	empty := h.emptyCells(cfg)
+
+	var step RespinStep
+
+	if len(empty) > 0 {
+		lands, err := cfg.Land.MultiPick(len(empty))
+		if err != nil {
+			return err
+		}
+
+		landed := lo.Filter(empty, func(_ Position, i int) bool { return lands[i] })
+
+		if len(landed) > 0 {
+			values, err := cfg.Coins.MultiPick(len(landed))
+			if err != nil {
+				return err
+			}
+
+			for i, p := range landed {
+				step.Coins = append(step.Coins, Coin{Position: p, Value: values[i]})
+			}
+		}
+	}
+
+	h.Locked = append(h.Locked, step.Coins...)
+
+	if len(step.Coins) > 0 {
+		h.Left = cfg.Respins
+	} else {
+		h.Left--
+	}
+
+	h.checkFullGrid(cfg)
+
+	step.Left = h.Left
+	h.Steps = append(h.Steps, step)
+
+	return nil
+}
+
+// Play plays all the respins.
+func (h *HoldAndWin) Play(cfg HoldAndWinConfig) error {
+	for h.Active() {
+		if err := h.Respin(cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Award returns the award of the locked coins and jackpots for the wager.
+func (h *HoldAndWin) Award(cfg HoldAndWinConfig, wager int64) (int64, error) {
+	var multiplier int64
+
+	for _, coin := range h.Locked {
+		if coin.Value.Jackpot == "" {
+			multiplier += coin.Value.Value
+
+			continue
+		}
+
+		jackpot, ok := cfg.Jackpots[coin.Value.Jackpot]
+		if !ok {
+			return 0, fmt.Errorf("unknown jackpot %s", coin.Value.Jackpot)
+		}
+
+		multiplier += jackpot
+	}
+
+	if h.Jackpot != "" {
+		jackpot, ok := cfg.Jackpots[h.Jackpot]
+		if !ok {
+			return 0, fmt.Errorf("unknown jackpot %s", h.Jackpot)
+		}
+
+		multiplier += jackpot
+	}
+
+	return multiplier * wager / orOne(cfg.Divider), nil
+}
+
+// RespinSteps is the number of recorded respins, it is used by RespinRestoringIndexes.
+func (h *HoldAndWin) RespinSteps() int {
+	return len(h.Steps)
+}
+
+func (h *HoldAndWin) DeepCopy() *HoldAndWin {
+	cp := *h
+	cp.Locked = append([]Coin(nil), h.Locked...)
+	cp.Steps = append([]RespinStep(nil), h.Steps...)
+
+	return &cp
+}
+
+// checkFullGrid finishes the feature without the empty cells and awards the full grid jackpot.
+func (h *HoldAndWin) checkFullGrid(cfg HoldAndWinConfig) {
+	if len(h.Locked) < cfg.Width*cfg.Height {
+		return
+	}
+
+	h.Left = 0
+	h.Jackpot = cfg.FullGridJackpot
+}
+
+func (h *HoldAndWin) emptyCells(cfg HoldAndWinConfig) []Position {
+	locked := lo.SliceToMap(h.Locked, func(item Coin) (Position, struct{}) {
+		return item.Position, struct{}{}
+	})
+
+	var empty []Position
+
+	for i := 0; i < cfg.Width; i++ {
+		for j := 0; j < cfg.Height; j++ {
+			if _, ok := locked[Position{Reel: i, Row: j}]; !ok {
+				empty = append(empty, Position{Reel: i, Row: j})
+			}
+		}
+	}
+
+	return empty
+}
+
+// RespinStepsCounter is implemented by spins with the hold and win feature.
+type RespinStepsCounter interface {
+	RespinSteps() int
+}
+
+// RespinRestoringIndexes tracks how many respin steps the player has watched.
+type RespinRestoringIndexes struct {
+	BaseSpinShown bool `json:"base_spin_shown"`
+	StepsShown    int  `json:"steps_shown"`
+}
+
+func (r *RespinRestoringIndexes) IsShown(spin engine.Spin) bool {
+	if !r.BaseSpinShown {
+		return false
+	}
+
+	counter, ok := spin.(RespinStepsCounter)
+	if !ok {
+		return true
+	}
+
+	return r.StepsShown >= counter.RespinSteps()
+}
+
+func (r *RespinRestoringIndexes) Update(payload interface{}) error {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, r)
+}
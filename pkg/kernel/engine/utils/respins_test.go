@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	baseUtils "bitbucket.org/play-workspace/base-slot-server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HoldAndWin(t *testing.T) {
+	client, err := rng.NewMockClient(nil)
+	require.NoError(t, err)
+
+	always, err := baseUtils.NewChooserFromMap(client, map[bool]int64{true: 1})
+	require.NoError(t, err)
+
+	never, err := baseUtils.NewChooserFromMap(client, map[bool]int64{false: 1})
+	require.NoError(t, err)
+
+	coins, err := baseUtils.NewChooserFromMap(client, map[CoinValue]int64{{Value: 2}: 1})
+	require.NoError(t, err)
+
+	cfg := HoldAndWinConfig{
+		Width: 3, Height: 2, Respins: 3,
+		Land: never, Coins: coins,
+		Jackpots:        map[string]int64{"grand": 1000},
+		FullGridJackpot: "grand",
+	}
+	trigger := []Coin{{Position: Position{Reel: 0, Row: 0}, Value: CoinValue{Value: 5}}}
+
+	t.Run("no new coins", func(t *testing.T) {
+		hw, err := NewHoldAndWin(cfg, trigger)
+		require.NoError(t, err)
+		require.NoError(t, hw.Play(cfg))
+		require.Len(t, hw.Steps, 3)
+		require.ErrorIs(t, hw.Respin(cfg), ErrRespinsFinished)
+
+		award, err := hw.Award(cfg, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(50), award)
+
+		ri := &RespinRestoringIndexes{}
+		require.NoError(t, ri.Update(map[string]interface{}{"base_spin_shown": true, "steps_shown": 2}))
+		require.Equal(t, 2, ri.StepsShown)
+	})
+
+	t.Run("full grid", func(t *testing.T) {
+		cfg.Land = always
+
+		hw, err := NewHoldAndWin(cfg, trigger)
+		require.NoError(t, err)
+		require.NoError(t, hw.Play(cfg))
+		require.Len(t, hw.Steps, 1)
+		require.Len(t, hw.Steps[0].Coins, 5)
+		require.Equal(t, "grand", hw.Jackpot)
+
+		award, err := hw.Award(cfg, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64((5+5*2+1000)*10), award)
+	})
+
+	t.Run("full grid without jackpot", func(t *testing.T) {
+		cfg := cfg
+		cfg.Land, cfg.FullGridJackpot = always, ""
+
+		hw, err := NewHoldAndWin(cfg, trigger)
+		require.NoError(t, err)
+		require.NoError(t, hw.Play(cfg))
+		require.Len(t, hw.Steps, 1)
+		require.False(t, hw.Active())
+		require.Empty(t, hw.Jackpot)
+	})
+
+	t.Run("invalid trigger", func(t *testing.T) {
+		_, err := NewHoldAndWin(cfg, []Coin{{Position: Position{Reel: 3, Row: 0}}})
+		require.ErrorContains(t, err, "out of grid")
+
+		_, err = NewHoldAndWin(cfg, []Coin{{Position: Position{Reel: 0, Row: -1}}})
+		require.ErrorContains(t, err, "out of grid")
+
+		_, err = NewHoldAndWin(cfg, append(trigger, trigger...))
+		require.ErrorContains(t, err, "duplicated trigger coin")
+	})
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+type Position struct {
+	Reel int `json:"reel"`
+	Row  int `json:"row"`
+}
+
+// Wilds is the state of sticky, expanding and walking wilds. Embed it into the spin to serialize it,
+// the board is always rebuilt from the reels stops and this state, so restoring reproduces the exact board.
+// Sticky wilds stay until Reset, expanded reels are kept for one spin and walking wilds move on every Walk.
+//
+//	base := NewWindow(stops)                     // window without wilds mechanics
+//	spin.Wilds.Walk(utils.RightToLeftDirection, width)
+//	spin.Wilds.Stick(utils.FindSymbol(base, wild))
+//	spin.Wilds.Expand(utils.FindSymbol(base, expandingWild))
+//	window := utils.NewWildMegaWaysWindow(base, wild, spin.Wilds)
+type Wilds struct {
+	Sticky   []Position `json:"sticky,omitempty"`
+	Expanded []int      `json:"expanded,omitempty"` // reels
+	Walking  []Position `json:"walking,omitempty"`
+}
+
+// Stick adds new sticky wilds, they stay on the board until the state is reset.
+func (w *Wilds) Stick(positions []Position) {
+	w.Sticky = uniquePositions(append(w.Sticky, positions...))
+}
+
+// Expand covers the whole reels of the positions with wilds for the spin, it replaces the reels of the previous spin.
+// Call it on every spin, no positions clear the expanded reels.
+func (w *Wilds) Expand(positions []Position) {
+	expanded := make([]int, 0, len(positions))
+
+	for _, p := range positions {
+		expanded = append(expanded, p.Reel)
+	}
+
+	w.Expanded = lo.Uniq(expanded)
+	sort.Ints(w.Expanded)
+}
+
+// AddWalking adds new walking wilds, they move by one reel on every Walk call.
+func (w *Wilds) AddWalking(positions []Position) {
+	w.Walking = uniquePositions(append(w.Walking, positions...))
+}
+
+// Walk moves walking wilds by one reel in the direction and removes the wilds that left the board.
+func (w *Wilds) Walk(direction string, width int) {
+	step := 1
+	if direction == RightToLeftDirection {
+		step = -1
+	}
+
+	walking := make([]Position, 0, len(w.Walking))
+
+	for _, p := range w.Walking {
+		p.Reel += step
+
+		if p.Reel >= 0 && p.Reel < width {
+			walking = append(walking, p)
+		}
+	}
+
+	w.Walking = walking
+}
+
+// Empty returns true if there are no wilds on the board.
+func (w *Wilds) Empty() bool {
+	return len(w.Sticky) == 0 && len(w.Expanded) == 0 && len(w.Walking) == 0
+}
+
+func (w *Wilds) Reset() {
+	*w = Wilds{}
+}
+
+// covers returns true if the cell is covered by any wild of the state.
+func (w *Wilds) covers(reelIndex, rowIndex int) bool {
+	p := Position{Reel: reelIndex, Row: rowIndex}
+
+	return lo.Contains(w.Expanded, reelIndex) || lo.Contains(w.Sticky, p) || lo.Contains(w.Walking, p)
+}
+
+// FindSymbol returns positions of the symbol on the window.
+func FindSymbol[Symbol comparable](window MegaWaysWindow[Symbol], symbol Symbol) []Position {
+	var positions []Position
+
+	for i := 0; i < window.GetWidth(); i++ {
+		for j := 0; j < window.GetHeight(i); j++ {
+			if window.GetSymbol(i, j) == symbol {
+				positions = append(positions, Position{Reel: i, Row: j})
+			}
+		}
+	}
+
+	return positions
+}
+
+// ApplyWilds returns a copy of the matrix (reel -> rows) with wilds placed according to the state.
+func ApplyWilds[Symbol comparable](matrix [][]Symbol, wild Symbol, state Wilds) [][]Symbol {
+	res := make([][]Symbol, len(matrix))
+
+	for i := range matrix {
+		res[i] = make([]Symbol, len(matrix[i]))
+
+		for j := range matrix[i] {
+			res[i][j] = lo.Ternary(state.covers(i, j), wild, matrix[i][j])
+		}
+	}
+
+	return res
+}
+
+type wildMegaWaysWindow[Symbol comparable] struct {
+	MegaWaysWindow[Symbol]
+	wild  Symbol
+	state Wilds
+}
+
+// NewWildMegaWaysWindow wraps the window, cells covered by the state return the wild symbol.
+func NewWildMegaWaysWindow[Symbol comparable](window MegaWaysWindow[Symbol], wild Symbol, state Wilds) MegaWaysWindow[Symbol] {
+	return &wildMegaWaysWindow[Symbol]{MegaWaysWindow: window, wild: wild, state: state}
+}
+
+func (w *wildMegaWaysWindow[Symbol]) GetSymbol(colIndex int, rowIndex int) Symbol {
+	if w.state.covers(colIndex, rowIndex) {
+		return w.wild
+	}
+
+	return w.MegaWaysWindow.GetSymbol(colIndex, rowIndex)
+}
+
+type wildWindow[PayItem, Symbol comparable] struct {
+	Window[PayItem, Symbol]
+	wild    Symbol
+	covered []map[PayItem]struct{} // reel -> pay items
+}
+
+// NewWildWindow wraps the window, cells covered by the state return the wild symbol.
+func NewWildWindow[PayItem, Symbol comparable](window Window[PayItem, Symbol], wild Symbol, state Wilds) Window[PayItem, Symbol] {
+	covered := make([]map[PayItem]struct{}, window.GetWidth())
+
+	for i := 0; i < window.GetWidth(); i++ {
+		covered[i] = map[PayItem]struct{}{}
+
+		for j := 0; j < window.GetHeight(); j++ {
+			if state.covers(i, j) {
+				payItem, _ := window.GetByIndexes(i, j)
+				covered[i][payItem] = struct{}{}
+			}
+		}
+	}
+
+	return &wildWindow[PayItem, Symbol]{Window: window, wild: wild, covered: covered}
+}
+
+func (w *wildWindow[PayItem, Symbol]) GetSymbol(reelIndex int, payItem PayItem) Symbol {
+	if _, ok := w.covered[reelIndex][payItem]; ok {
+		return w.wild
+	}
+
+	return w.Window.GetSymbol(reelIndex, payItem)
+}
+
+func (w *wildWindow[PayItem, Symbol]) GetByIndexes(reelIndex, symbolIndex int) (PayItem, Symbol) {
+	payItem, symbol := w.Window.GetByIndexes(reelIndex, symbolIndex)
+
+	if _, ok := w.covered[reelIndex][payItem]; ok {
+		return payItem, w.wild
+	}
+
+	return payItem, symbol
+}
+
+func uniquePositions(positions []Position) []Position {
+	positions = lo.Uniq(positions)
+
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Reel == positions[j].Reel {
+			return positions[i].Row < positions[j].Row
+		}
+
+		return positions[i].Reel < positions[j].Reel
+	})
+
+	return positions
+}
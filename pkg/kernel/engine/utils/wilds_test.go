@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_Wilds(t *testing.T) {
+	base := window{
+		{1, 2, 3},
+		{2, wild, 1},
+		{3, 3, 2},
+	}
+
+	var state Wilds
+
+	state.Stick(FindSymbol[int](base, wild))
+	state.AddWalking([]Position{{Reel: 2, Row: 0}})
+	state.Expand([]Position{{Reel: 0, Row: 2}})
+
+	want := [][]int{
+		{wild, wild, wild},
+		{2, wild, 1},
+		{wild, 3, 2},
+	}
+
+	if got := ApplyWilds(base, wild, state); !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyWilds() = %v, want %v", got, want)
+	}
+
+	state.Walk(RightToLeftDirection, base.GetWidth())
+
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restored Wilds
+	if err := json.Unmarshal(bytes, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWildMegaWaysWindow[int](base, wild, restored)
+	if w.GetSymbol(1, 0) != wild || w.GetSymbol(2, 0) != 3 || w.GetSymbol(1, 1) != wild {
+		t.Errorf("NewWildMegaWaysWindow() does not reproduce the board: %v", restored)
+	}
+
+	state.Walk(RightToLeftDirection, base.GetWidth())
+	state.Walk(RightToLeftDirection, base.GetWidth())
+
+	if len(state.Walking) != 0 {
+		t.Errorf("Walk() = %v, want walking wild to leave the board", state.Walking)
+	}
+}
+
+func Test_NewWildWindow(t *testing.T) {
+	base := lineWindow{
+		{1, 2, 3},
+		{1, 2, 1},
+		{3, 3, 2},
+	}
+	wildSymbol := wild
+
+	var state Wilds
+
+	state.Stick([]Position{{Reel: 1, Row: 2}})
+	state.Expand([]Position{{Reel: 2, Row: 1}})
+
+	w := NewWildWindow[int, int](base, wild, state)
+
+	// the 1st row pays 1 with the expanded reel, the 3rd row pays 3 with the sticky wild and the expanded reel
+	got := CalcBasePayLines([][]int{{0, 0, 0}, {2, 2, 2}}, w, flatAwardGetter{}, nil, &wildSymbol, LeftToRightDirection)
+	want := []PayLine[int, int]{
+		{PayLineItems: []int{0, 0, 0}, PaySymbol: 1, Direction: LeftToRightDirection, Award: 3, BaseAward: 3},
+		{PayLineIndex: 1, PayLineItems: []int{2, 2, 2}, PaySymbol: 3, Direction: LeftToRightDirection, Award: 9, BaseAward: 9},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CalcBasePayLines() = %+v, want %+v", got, want)
+	}
+
+	if _, symbol := w.GetByIndexes(2, 2); symbol != wild {
+		t.Errorf("GetByIndexes() = %d, want the expanded wild", symbol)
+	}
+
+	// the next spin without expanding wilds clears the expanded reel, the sticky wild stays
+	state.Expand(nil)
+
+	w = NewWildWindow[int, int](base, wild, state)
+
+	if w.GetSymbol(2, 1) != 3 || w.GetSymbol(1, 2) != wild {
+		t.Errorf("NewWildWindow() does not clear the expanded reel: %v", state)
+	}
+}
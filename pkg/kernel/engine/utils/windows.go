@@ -38,8 +38,20 @@ type AvalancheWindow[Symbol comparable] interface {
 	GetMultiplierQty() int
 }
 
+// WinIndexesWindow is an optional interface for AvalancheWindow, the avalanche deletes only the indexes of the win
+// (for example, the cluster with its wilds) instead of every instance of the symbol.
+type WinIndexesWindow interface {
+	// GetAbsIndexes converts indexes of the window (reel -> rows) to absolute indexes of the reels.
+	GetAbsIndexes(indexes [][]int) [][]int
+}
+
 type Win[Symbol comparable] interface {
 	GetSymbol() Symbol
 	GetIndexes() [][]int
 	Count() int
 }
+
+// WaysCounter is an optional interface for Win, the award of such win is multiplied by the number of ways.
+type WaysCounter interface {
+	GetWays() int
+}
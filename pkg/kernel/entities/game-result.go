@@ -13,6 +13,8 @@ type GameResult struct {
 	RestoringIndexes engine.RestoringIndexes `json:"restoring_indexes" mapstructure:"restoring_indexes"`
 
 	IsPFR bool `json:"is_pfr" mapstructure:"is_pfr"`
+	// MaxWin is the paid award of the spin if the max win is reached.
+	MaxWin *MaxWin `json:"max_win,omitempty" mapstructure:"max_win,omitempty"`
 	// computed
 	CanGamble bool `json:"can_gamble" mapstructure:"can_gamble"`
 	computed  bool
@@ -20,8 +22,36 @@ type GameResult struct {
 	currencyMultiplier int64
 }
 
-func NewGameResult(id uuid.UUID, spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, currencyMultiplier int64) *GameResult {
-	return &GameResult{ID: id, Spin: spin, RestoringIndexes: restoringIndexes, IsPFR: isPFR, currencyMultiplier: currencyMultiplier}
+func NewGameResult(id uuid.UUID, spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, maxWin *MaxWin, currencyMultiplier int64) *GameResult {
+	return &GameResult{
+		ID:                 id,
+		Spin:               spin,
+		RestoringIndexes:   restoringIndexes,
+		IsPFR:              isPFR,
+		MaxWin:             maxWin,
+		currencyMultiplier: currencyMultiplier,
+	}
+}
+
+// BaseAward is the paid base award of the spin, it is the stake of the gamble.
+func (gr *GameResult) BaseAward() int64 {
+	baseAward, _ := gr.MaxWin.Awards(gr.Spin)
+
+	return baseAward
+}
+
+// GambleStake is the stake of the next gamble: the award of the last gamble or the paid base award of the spin.
+func (gr *GameResult) GambleStake() int64 {
+	if gambles := gr.Spin.GetGamble(); gambles.Len() > 0 {
+		return gambles.Award()
+	}
+
+	return gr.BaseAward()
+}
+
+// TotalAward is the paid award of the spin without gambling.
+func (gr *GameResult) TotalAward() int64 {
+	return gr.MaxWin.TotalAward(gr.Spin)
 }
 
 func (gr *GameResult) GetCanGable(gambleDoubleUp int64) bool {
@@ -35,7 +65,7 @@ func (gr *GameResult) Compute(gambleDoubleUp *int64) {
 
 	// cannot gamble if:
 	// 1. the base award is 0
-	baseAwardZero := gr.Spin.BaseAward() == 0
+	baseAwardZero := gr.BaseAward() == 0
 
 	// 2. gamble limit reached
 	exceededLimit := false
@@ -43,20 +73,20 @@ func (gr *GameResult) Compute(gambleDoubleUp *int64) {
 		exceededLimit = gambles.Len() >= int(*gambleDoubleUp)
 	}
 
-	// 3. bonus triggered
-	bonusTriggered := gr.Spin.BonusTriggered()
+	// 3. bonus triggered or jackpot won
+	bonusTriggered := gr.Spin.BonusTriggered() || engine.JackpotAward(gr.Spin) > 0
 
 	// 4. gamble is collected (check restoring indexes)
 	gambleCollected := gr.Spin.CanGamble(gr.RestoringIndexes)
 
-	// 5. last gamble was lost
-	var lastGambleWasLost bool
-	if gambles.Len() > 0 {
-		lastGambleWasLost = gambles.Last().Award == 0
-	}
+	// 5. last gamble was lost or reached the max win
+	lastGambleWasLost := gambles.Closed()
+
+	// 6. the spin reached the max win, the gamble can not win more
+	maxWinReached := gr.MaxWin != nil
 
 	gr.computed = true
-	gr.CanGamble = !(baseAwardZero || exceededLimit || bonusTriggered || lastGambleWasLost) && gambleCollected
+	gr.CanGamble = !(baseAwardZero || exceededLimit || bonusTriggered || lastGambleWasLost || maxWinReached) && gambleCollected
 }
 
 func (gr *GameResult) MarshalJSON() ([]byte, error) {
@@ -83,3 +113,32 @@ func (gr *GameResult) View() map[string]interface{} {
 
 	return view
 }
+
+// Clone returns the copy of the result which can be mutated without changing the original.
+// The spin is copied by its DeepCopy, the restoring indexes have no DeepCopy,
+// they are copied through json by the factory like the restored ones.
+func (gr *GameResult) Clone(factory engine.SpinFactory) (*GameResult, error) {
+	cp := *gr
+
+	if gr.MaxWin != nil {
+		maxWin := *gr.MaxWin
+		cp.MaxWin = &maxWin
+	}
+
+	if gr.Spin != nil {
+		cp.Spin = gr.Spin.DeepCopy()
+	}
+
+	if gr.RestoringIndexes != nil {
+		b, err := json.Marshal(gr.RestoringIndexes)
+		if err != nil {
+			return nil, err
+		}
+
+		if cp.RestoringIndexes, err = factory.UnmarshalJSONRestoringIndexes(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cp, nil
+}
@@ -53,6 +53,17 @@ type GameState struct {
 	LowBalance   bool   `json:"low_balance"`
 	ShortLink    bool   `json:"short_link"`
 	MinWager     int64  `json:"min_wager"`
+
+	// RealityCheck is set if the jurisdiction requires the reality check messages.
+	RealityCheck *RealityCheck `json:"reality_check,omitempty"`
+}
+
+// RealityCheck is the interval of the reality check messages and the session summary for them.
+type RealityCheck struct {
+	Interval int64 `json:"interval"` // seconds
+	Elapsed  int64 `json:"elapsed"`  // seconds since the first spin of the session
+	Wagered  int64 `json:"wagered"`
+	Won      int64 `json:"won"`
 }
 
 func (gs *GameState) Compute() *GameState {
@@ -63,6 +74,23 @@ func (gs *GameState) Compute() *GameState {
 	return gs
 }
 
+// Clone returns the copy of the state with the copied game results, the other fields are shared.
+func (gs *GameState) Clone(factory engine.SpinFactory) (*GameState, error) {
+	cp := *gs
+	cp.GameResults = make(GameResults, 0, len(gs.GameResults))
+
+	for _, result := range gs.GameResults {
+		clone, err := result.Clone(factory)
+		if err != nil {
+			return nil, err
+		}
+
+		cp.GameResults = append(cp.GameResults, clone)
+	}
+
+	return &cp, nil
+}
+
 func (gs *GameState) SetEngineInfo(engineInfo interface{}) *GameState {
 	gs.EngineInfo = engineInfo
 
@@ -75,49 +103,57 @@ func (gs *GameState) SetBootInfo(bootInfo interface{}) *GameState {
 	return gs
 }
 
-func (gs *GameState) SetGeneratedSpin(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, newBalance int64, roundID uuid.UUID) *HistoryRecord {
-	oldBalance := newBalance - engine.TotalAward(spin) + spin.Wager()
+// SetGeneratedSpin sets the paid spin, maxWin is the capped award of the spin or nil if the max win is not reached.
+func (gs *GameState) SetGeneratedSpin(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, maxWin *MaxWin, newBalance int64, roundID uuid.UUID) *HistoryRecord {
+	oldBalance := newBalance - maxWin.TotalAward(spin) + spin.Wager()
 
-	return gs.setGeneratedSpin(spin, restoringIndexes, isPFR, newBalance, oldBalance, roundID)
+	return gs.setGeneratedSpin(spin, restoringIndexes, isPFR, maxWin, newBalance, oldBalance, roundID)
 }
 
-func (gs *GameState) SetGeneratedFreeSpin(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, newBalance int64, roundID uuid.UUID) *HistoryRecord {
+func (gs *GameState) SetGeneratedFreeSpin(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, maxWin *MaxWin, newBalance int64, roundID uuid.UUID) *HistoryRecord {
 	oldBalance := newBalance
 	if newBalance != 0 {
-		oldBalance = newBalance - engine.TotalAward(spin)
+		oldBalance = newBalance - maxWin.TotalAward(spin)
 	}
 
-	return gs.setGeneratedSpin(spin, restoringIndexes, isPFR, newBalance, oldBalance, roundID)
+	return gs.setGeneratedSpin(spin, restoringIndexes, isPFR, maxWin, newBalance, oldBalance, roundID)
+}
+
+// PendingRecord returns the history record of the spin before the bet without changing the game state,
+// the transaction and the balances of the record are known after the bet.
+func (gs *GameState) PendingRecord(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, maxWin *MaxWin, roundID uuid.UUID) *HistoryRecord {
+	return gs.extractHistoryRecord(spin, restoringIndexes, isPFR, maxWin, gs.Balance, gs.Balance, roundID)
 }
 
 // UpdateLastSpin is a function for gamble feature that returns history records
-// with the correct start balance and end balance
-func (gs *GameState) UpdateLastSpin(newSpin engine.Spin, newBalance int64) *HistoryRecord {
+// with the correct start balance and end balance, maxWin is the capped award of the new spin.
+func (gs *GameState) UpdateLastSpin(newSpin engine.Spin, maxWin *MaxWin, newBalance int64) *HistoryRecord {
 	if len(gs.GameResults) == 0 {
 		return nil
 	}
 
 	oldRes := gs.GameResults[len(gs.GameResults)-1]
-	oldBalance := newBalance - engine.TotalAwardWithGambling(newSpin) + newSpin.Wager()
+	oldBalance := newBalance - maxWin.TotalAwardWithGambling(newSpin) + newSpin.Wager()
 
 	if oldRes.IsPFR {
 		oldBalance -= newSpin.Wager()
 	}
 
 	oldRes.Spin = newSpin
+	oldRes.MaxWin = maxWin
 	gs.GameResults[len(gs.GameResults)-1] = oldRes
 
-	hr := gs.extractHistoryRecord(oldRes.Spin, oldRes.RestoringIndexes, oldRes.IsPFR, newBalance, oldBalance, oldRes.ID)
+	hr := gs.extractHistoryRecord(oldRes.Spin, oldRes.RestoringIndexes, oldRes.IsPFR, maxWin, newBalance, oldBalance, oldRes.ID)
 
 	gs.Balance = newBalance
 
 	return hr
 }
 
-func (gs *GameState) setGeneratedSpin(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, newBalance, oldBalance int64, roundID uuid.UUID) *HistoryRecord {
-	hr := gs.extractHistoryRecord(spin, restoringIndexes, isPFR, newBalance, oldBalance, roundID)
+func (gs *GameState) setGeneratedSpin(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, maxWin *MaxWin, newBalance, oldBalance int64, roundID uuid.UUID) *HistoryRecord {
+	hr := gs.extractHistoryRecord(spin, restoringIndexes, isPFR, maxWin, newBalance, oldBalance, roundID)
 
-	ngr := NewGameResult(hr.ID, spin, restoringIndexes, hr.IsPFR, gs.CurrencyMultiplier)
+	ngr := NewGameResult(hr.ID, spin, restoringIndexes, hr.IsPFR, maxWin, gs.CurrencyMultiplier)
 
 	if engine.GetFromContainer().HistoryHandlingType == engine.ParallelRestoring {
 		gs.GameResults = append(gs.GameResults, ngr)
@@ -130,7 +166,9 @@ func (gs *GameState) setGeneratedSpin(spin engine.Spin, restoringIndexes engine.
 	return hr
 }
 
-func (gs *GameState) extractHistoryRecord(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, newBalance, oldBalance int64, roundID uuid.UUID) *HistoryRecord {
+func (gs *GameState) extractHistoryRecord(spin engine.Spin, restoringIndexes engine.RestoringIndexes, isPFR bool, maxWin *MaxWin, newBalance, oldBalance int64, roundID uuid.UUID) *HistoryRecord {
+	baseAward, bonusAward := maxWin.Awards(spin)
+
 	return &HistoryRecord{
 		ID:             roundID,
 		Game:           gs.Game,
@@ -146,9 +184,12 @@ func (gs *GameState) extractHistoryRecord(spin engine.Spin, restoringIndexes eng
 		StartBalance: oldBalance,
 		EndBalance:   newBalance,
 		Wager:        spin.Wager(),
-		BaseAward:    spin.BaseAward(),
-		BonusAward:   spin.BonusAward(),
-		FinalAward:   engine.TotalAwardWithGambling(spin),
+		BaseAward:    baseAward,
+		BonusAward:   bonusAward,
+		JackpotAward: engine.JackpotAward(spin),
+		FinalAward:   maxWin.TotalAwardWithGambling(spin),
+
+		MaxWinReached: maxWin != nil,
 
 		Spin:             spin,
 		RestoringIndexes: restoringIndexes,
@@ -289,11 +330,7 @@ func GameStateFromLordState(state *overlord.InitUserStateOut) (*GameState, error
 
 func filterWagers(state *overlord.InitUserStateOut, cfg *engine.Bootstrap) []int64 {
 	return lo.Filter(state.WagerLevels, func(item int64, index int) bool {
-		ok := true
-
-		if cfg.AnteBetMultiplier > 0 {
-			ok = item*cfg.AnteBetMultiplier/100%10 == 0
-		}
+		ok := cfg.WagerAllowed(item)
 
 		if ok && cfg.GameMaxWager > 0 {
 			ok = item <= cfg.GameMaxWager*state.CurrencyMultiplier
@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+)
+
+type testSpin struct {
+	Base     int64          `json:"base"`
+	Bonus    int64          `json:"bonus"`
+	Wagered  int64          `json:"wager"`
+	Window   [][]int        `json:"window"`
+	Gambles  *engine.Gamble `json:"gambles"`
+	Triggers bool           `json:"triggers"`
+}
+
+func (s *testSpin) BaseAward() int64                         { return s.Base }
+func (s *testSpin) BonusAward() int64                        { return s.Bonus }
+func (s *testSpin) OriginalWager() int64                     { return s.Wagered }
+func (s *testSpin) Wager() int64                             { return s.Wagered }
+func (s *testSpin) BonusTriggered() bool                     { return s.Triggers }
+func (s *testSpin) CanGamble(_ engine.RestoringIndexes) bool { return true }
+
+func (s *testSpin) GetGamble() *engine.Gamble {
+	if s.Gambles == nil {
+		s.Gambles = &engine.Gamble{}
+	}
+
+	return s.Gambles
+}
+
+func (s *testSpin) DeepCopy() engine.Spin {
+	cp := *s
+	cp.Window = make([][]int, len(s.Window))
+
+	for i := range s.Window {
+		cp.Window[i] = append([]int(nil), s.Window[i]...)
+	}
+
+	if s.Gambles != nil {
+		gambles := make(engine.Gamble, 0, s.Gambles.Len())
+		for _, item := range *s.Gambles {
+			gi := *item
+			gambles = append(gambles, &gi)
+		}
+
+		cp.Gambles = &gambles
+	}
+
+	return &cp
+}
+
+type testIndexes struct {
+	Shown bool `json:"shown"`
+}
+
+func (r *testIndexes) IsShown(engine.Spin) bool { return r.Shown }
+
+func (r *testIndexes) Update(payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, r)
+}
+
+type testFactory struct{}
+
+func (testFactory) Generate(engine.Context, int64, interface{}) (engine.Spin, engine.RestoringIndexes, error) {
+	return &testSpin{}, &testIndexes{}, nil
+}
+
+func (testFactory) KeepGenerate(engine.Context, interface{}) (engine.Spin, bool, error) {
+	return nil, false, nil
+}
+
+func (testFactory) UnmarshalJSONSpin(bytes []byte) (engine.Spin, error) {
+	spin := &testSpin{}
+
+	return spin, json.Unmarshal(bytes, spin)
+}
+
+func (testFactory) UnmarshalJSONRestoringIndexes(bytes []byte) (engine.RestoringIndexes, error) {
+	indexes := &testIndexes{}
+
+	return indexes, json.Unmarshal(bytes, indexes)
+}
+
+func (testFactory) GetRngClient() rng.Client {
+	client, _ := rng.NewMockClient(nil)
+
+	return client
+}
+
+func TestMain(m *testing.M) {
+	engine.PutInContainer(&engine.Bootstrap{
+		SpinFactory:         testFactory{},
+		GambleAnyWinFeature: true,
+		HistoryHandlingType: engine.ParallelRestoring,
+	})
+
+	os.Exit(m.Run())
+}
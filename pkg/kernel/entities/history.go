@@ -32,8 +32,12 @@ type HistoryRecord struct {
 	Wager        int64  `json:"wager" mapstructure:"wager"`
 	BaseAward    int64  `json:"base_award" mapstructure:"base_award"`
 	BonusAward   int64  `json:"bonus_award" mapstructure:"bonus_award"`
+	JackpotAward int64  `json:"jackpot_award" mapstructure:"jackpot_award"`
 	FinalAward   int64  `json:"final_award" mapstructure:"final_award"`
 
+	// MaxWinReached is set if the awards of the record are capped by the max win, the spin keeps the original ones.
+	MaxWinReached bool `json:"max_win_reached" mapstructure:"max_win_reached"`
+
 	Spin             engine.Spin             `json:"spin" gorm:"serializer:spin" mapstructure:"spin"`
 	RestoringIndexes engine.RestoringIndexes `json:"restoring_indexes" gorm:"serializer:restoring" mapstructure:"restoring_indexes"`
 
@@ -150,6 +154,12 @@ func FromHistoryServiceItem(spin *history.SpinOut, factory engine.SpinFactory) (
 		return nil, err
 	}
 
+	// the history keeps the paid awards, they are less than the spin awards if the max win is reached
+	maxWinReached := int64(spin.BaseAward+spin.BonusAward) < spinDetails.BaseAward()+spinDetails.BonusAward()
+	if capper, ok := spinDetails.(engine.MaxWinCapper); ok && capper.MaxWinReached() {
+		maxWinReached = true
+	}
+
 	return &HistoryRecord{
 		CreatedAt: spin.CreatedAt.AsTime(),
 		UpdatedAt: spin.UpdatedAt.AsTime(),
@@ -171,8 +181,11 @@ func FromHistoryServiceItem(spin *history.SpinOut, factory engine.SpinFactory) (
 		Wager:        int64(spin.Wager),
 		BaseAward:    int64(spin.BaseAward),
 		BonusAward:   int64(spin.BonusAward),
+		JackpotAward: engine.JackpotAward(spinDetails),
 		FinalAward:   int64(spin.FinalAward),
 
+		MaxWinReached: maxWinReached,
+
 		Spin:             spinDetails,
 		RestoringIndexes: restoringIndexes,
 
@@ -188,7 +201,12 @@ func (hr *HistoryRecord) SetTransactionID(transactionID uuid.UUID) {
 }
 
 func (hr *HistoryRecord) ExtractGameResult(currencyMultiplier int64) *GameResult {
-	return NewGameResult(hr.ID, hr.Spin, hr.RestoringIndexes, hr.IsPFR, currencyMultiplier)
+	var maxWin *MaxWin
+	if hr.MaxWinReached {
+		maxWin = &MaxWin{BaseAward: hr.BaseAward, BonusAward: hr.BonusAward}
+	}
+
+	return NewGameResult(hr.ID, hr.Spin, hr.RestoringIndexes, hr.IsPFR, maxWin, currencyMultiplier)
 }
 
 type HistoryPagination struct {
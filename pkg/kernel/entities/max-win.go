@@ -0,0 +1,46 @@
+package entities
+
+import "bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+
+// MaxWin is the paid award of the spin truncated by the max win of the game. The spins which do not implement
+// engine.MaxWinCapper keep the original award, so the capped award is kept in the game result and the history.
+// The nil MaxWin means the max win is not reached and the spin awards are paid.
+type MaxWin struct {
+	BaseAward  int64 `json:"base_award" mapstructure:"base_award"`
+	BonusAward int64 `json:"bonus_award" mapstructure:"bonus_award"`
+}
+
+// ApplyMaxWin caps the award of the spin by maxWin, it returns nil if the max win is not reached.
+func ApplyMaxWin(spin engine.Spin, maxWin int64) *MaxWin {
+	baseAward, bonusAward, reached := engine.ApplyMaxWin(spin, maxWin)
+	if !reached {
+		return nil
+	}
+
+	return &MaxWin{BaseAward: baseAward, BonusAward: bonusAward}
+}
+
+// Awards returns the paid base and bonus awards of the spin.
+func (m *MaxWin) Awards(spin engine.Spin) (baseAward, bonusAward int64) {
+	if m == nil {
+		return spin.BaseAward(), spin.BonusAward()
+	}
+
+	return m.BaseAward, m.BonusAward
+}
+
+// TotalAward is engine.TotalAward with the capped awards, jackpots are paid over the max win.
+func (m *MaxWin) TotalAward(spin engine.Spin) int64 {
+	baseAward, bonusAward := m.Awards(spin)
+
+	return baseAward + bonusAward + engine.JackpotAward(spin)
+}
+
+// TotalAwardWithGambling is engine.TotalAwardWithGambling with the capped awards.
+func (m *MaxWin) TotalAwardWithGambling(spin engine.Spin) int64 {
+	if spin.GetGamble().Len() == 0 {
+		return m.TotalAward(spin)
+	}
+
+	return spin.GetGamble().Award()
+}
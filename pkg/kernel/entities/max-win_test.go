@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameState_SetGeneratedSpin_MaxWin(t *testing.T) {
+	gs := &GameState{UserID: uuid.New(), SessionToken: uuid.New(), Balance: 1000}
+	spin := &testSpin{Base: 3000, Bonus: 9000, Wagered: 1}
+
+	maxWin := ApplyMaxWin(spin, 5000)
+	require.Equal(t, &MaxWin{BaseAward: 3000, BonusAward: 2000}, maxWin)
+
+	record := gs.SetGeneratedSpin(spin, &testIndexes{Shown: true}, false, maxWin, 5999, uuid.New())
+
+	require.True(t, record.MaxWinReached)
+	require.Equal(t, int64(3000), record.BaseAward)
+	require.Equal(t, int64(2000), record.BonusAward)
+	require.Equal(t, int64(5000), record.FinalAward)
+	require.Equal(t, int64(1000), record.StartBalance)
+	require.Equal(t, int64(5999), gs.Balance)
+
+	result, ok := gs.GameResults.Last()
+	require.True(t, ok)
+	require.Equal(t, maxWin, result.MaxWin)
+	require.Equal(t, int64(5000), result.TotalAward())
+	require.False(t, result.GetCanGable(10), "the spin at the max win can not be gambled")
+
+	restored := record.ExtractGameResult(1)
+	require.Equal(t, maxWin, restored.MaxWin)
+}
+
+func TestGameState_SetGeneratedSpin_NoMaxWin(t *testing.T) {
+	gs := &GameState{UserID: uuid.New(), SessionToken: uuid.New()}
+	spin := &testSpin{Base: 30, Wagered: 1}
+
+	maxWin := ApplyMaxWin(spin, 5000)
+	require.Nil(t, maxWin)
+
+	record := gs.SetGeneratedSpin(spin, &testIndexes{Shown: true}, false, maxWin, 129, uuid.New())
+
+	require.False(t, record.MaxWinReached)
+	require.Equal(t, int64(30), record.FinalAward)
+	require.Equal(t, int64(100), record.StartBalance)
+
+	result, _ := gs.GameResults.Last()
+	require.Nil(t, result.MaxWin)
+	require.True(t, result.GetCanGable(10))
+	require.Equal(t, int64(30), result.BaseAward())
+}
+
+func TestFromHistoryServiceItem_MaxWin(t *testing.T) {
+	gs := &GameState{UserID: uuid.New(), SessionToken: uuid.New(), GameID: uuid.New()}
+
+	for _, tt := range []struct {
+		name   string
+		spin   *testSpin
+		maxWin *MaxWin
+	}{
+		{name: "capped", spin: &testSpin{Base: 3000, Bonus: 9000, Wagered: 1}, maxWin: &MaxWin{BaseAward: 3000, BonusAward: 2000}},
+		{name: "not capped", spin: &testSpin{Base: 30, Wagered: 1}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			record := gs.SetGeneratedSpin(tt.spin, &testIndexes{Shown: true}, false, tt.maxWin, 10000, uuid.New())
+			record.SetTransactionID(uuid.New())
+
+			in, err := record.ToHistoryServiceIn(&PlayerMetaData{})
+			require.NoError(t, err)
+
+			restored, err := FromHistoryServiceItem(&history.SpinOut{
+				CreatedAt: in.CreatedAt, UpdatedAt: in.UpdatedAt,
+				Id: in.Id, GameId: in.GameId, SessionToken: in.SessionToken, TransactionId: in.TransactionId,
+				InternalUserId: in.InternalUserId,
+				Wager:          in.Wager, BaseAward: in.BaseAward, BonusAward: in.BonusAward, FinalAward: in.FinalAward,
+				RestoringIndexes: in.RestoringIndexes, Details: in.Details,
+				IsPfr: &in.IsPfr, IsShown: &in.IsShown, IsDemo: in.IsDemo,
+			}, testFactory{})
+			require.NoError(t, err)
+
+			require.Equal(t, tt.maxWin != nil, restored.MaxWinReached)
+			require.Equal(t, tt.maxWin, restored.ExtractGameResult(1).MaxWin)
+		})
+	}
+}
+
+func TestGameState_UpdateLastSpin_Gamble(t *testing.T) {
+	gs := &GameState{UserID: uuid.New(), SessionToken: uuid.New()}
+	spin := &testSpin{Base: 30, Wagered: 10}
+
+	gs.SetGeneratedSpin(spin, &testIndexes{Shown: true}, false, nil, 120, uuid.New())
+
+	result, _ := gs.GameResults.Last()
+
+	client, err := rng.NewMockClient(nil)
+	require.NoError(t, err)
+
+	gamble := spin.GetGamble()
+	require.NoError(t, gamble.PlayWithConfig(client, spin, map[string]any{"gamble_pick": 1}, map[string]any{"gamble_pick": 1},
+		engine.GambleConfig{Stake: result.BaseAward()}))
+
+	record := gs.UpdateLastSpin(spin, result.MaxWin, 150)
+
+	require.Equal(t, int64(60), record.FinalAward)
+	require.Equal(t, int64(100), record.StartBalance)
+	require.Equal(t, int64(150), gs.Balance)
+}
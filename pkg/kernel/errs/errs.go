@@ -38,7 +38,20 @@ var (
 	ErrGambleAnyWinWasDisabledOnServerLevel = errors.New("gamble any win was disabled on server level")
 	ErrLimitForGambleSetToZero              = errors.New("limit for gamble is set to 0")
 	ErrCanNotGamble                         = errors.New("can not gamble")
+	ErrGambleLost                           = errors.New("previous gamble was lost")
+	ErrGambleLimitReached                   = errors.New("gamble double up limit is reached")
+	ErrGambleMaxWinReached                  = errors.New("gamble max win is reached")
+	ErrNoWinToGamble                        = errors.New("no win to gamble")
 	ErrUserHasDifferentCurrency             = errors.New("user_has_different_currency")
+	ErrIdempotencyKeyReused                 = errors.New("idempotency key is used with another request")
+	ErrRequestInProgress                    = errors.New("request with the same idempotency key is in progress")
+
+	ErrStakeLimitExceeded      = errors.New("wager limit exceeded")
+	ErrSessionLossLimitReached = errors.New("session loss limit is reached")
+	ErrSpinTooFast             = errors.New("spin is faster than the minimum spin duration")
+	ErrAutoplayNotAllowed      = errors.New("autoplay is not allowed in the jurisdiction")
+	ErrAutoplayIsNotSupported  = errors.New("autoplay is not supported for the games with sequential restoring")
+	ErrTurboNotAllowed         = errors.New("turbo is not allowed in the jurisdiction")
 
 	ErrUserIsBlocked             = errors.New("user is blocked")
 	ErrIntegratorCriticalFailure = errors.New("integrator critical failure")
@@ -106,6 +106,8 @@ func MapErrorToGameHub(err error) (GameHubError, bool) {
 		return GameHubErrorMap[ErrCodeAuthFailed], true
 	case ErrUserHasDifferentCurrency:
 		return GameHubErrorMap[ErrCodeCurrency], true
+	case ErrStakeLimitExceeded, ErrSessionLossLimitReached, ErrSpinTooFast, ErrAutoplayNotAllowed, ErrTurboNotAllowed:
+		return GameHubErrorMap[ErrCodeWageringLimit], true
 	}
 
 	switch {
@@ -1,8 +1,11 @@
 package errs
 
 import (
+	"errors"
+
 	"bitbucket.org/play-workspace/base-slot-server/pkg/cryptolut_rgs"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/overlord"
 )
 
@@ -33,6 +36,13 @@ var translateHistoryMap = map[error]error{
 	history.ErrSpinNotFound: ErrHistoryRecordNotFound,
 }
 
+var translateGambleMap = map[error]error{
+	engine.ErrGambleLost:          ErrGambleLost,
+	engine.ErrGambleLimitReached:  ErrGambleLimitReached,
+	engine.ErrGambleMaxWinReached: ErrGambleMaxWinReached,
+	engine.ErrNoWinToGamble:       ErrNoWinToGamble,
+}
+
 func TranslateOverlordErr(err error) error {
 	validationErr, ok := err.(overlord.ValidationError)
 	if ok {
@@ -60,3 +70,16 @@ func TranslateHistoryErr(err error) error {
 
 	return res
 }
+
+func TranslateGambleErr(err error) error {
+	if errors.Is(err, engine.ErrUnknownGambleType) || errors.Is(err, engine.ErrInvalidGamblePick) {
+		return InternalValidationError{Err: err}
+	}
+
+	res, ok := translateGambleMap[err]
+	if !ok {
+		return err
+	}
+
+	return res
+}
@@ -0,0 +1,168 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/services"
+)
+
+// MaxAutoplaySpins limits the spins of one autoplay request, the client continues the autoplay with the next request.
+const MaxAutoplaySpins = 100
+
+// MaxAutoplayBatchDuration limits the time of the autoplay which returns the rounds in one response,
+// so the response is written before the write timeout of the server.
+const MaxAutoplayBatchDuration = 20 * time.Second
+
+const (
+	AutoplayStopSpinsDone = "spins_done"
+	AutoplayStopLossLimit = "loss_limit"
+	AutoplayStopWinLimit  = "single_win_limit"
+	AutoplayStopBonus     = "bonus"
+	AutoplayStopBalance   = "balance"
+	AutoplayStopTimeLimit = "time_limit"
+	AutoplayStopCancelled = "cancelled"
+	AutoplayStopError     = "error"
+)
+
+// Autoplay plays the rounds of the request one by one until the spins are done or a stop condition is met.
+// onRound is called after every round, the rounds are also returned in the response if it is nil
+// and the autoplay is limited by MaxAutoplayBatchDuration.
+// The error is returned only if no round is played, otherwise it is the stop reason of the response.
+// The rounds of the games with sequential restoring are shown by the client one by one, they can not be autoplayed.
+func (facade *Facade) Autoplay(ctx context.Context, payload interface{}, metaData *entities.PlayerMetaData,
+	onRound func(round *AutoplayRound) error) (*AutoplayResponse, error) {
+	if err := facade.validatePlayerMetadata(metaData); err != nil {
+		return nil, err
+	}
+
+	if facade.boot.HistoryHandlingType == engine.SequentialRestoring {
+		return nil, errs.ErrAutoplayIsNotSupported
+	}
+
+	req := AutoplayRequest{}
+	if err := parseRequest(payload, &req, facade.validationEngine); err != nil {
+		return nil, err
+	}
+
+	wagerReq := WagerRequest{
+		SessionToken: req.SessionToken,
+		Wager:        req.Wager,
+		FreeSpinID:   req.FreeSpinID,
+		EngineParams: req.EngineParams,
+	}
+
+	gameState, err := facade.wagerState(ctx, wagerReq)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := services.PlayMode{Autoplay: true, Turbo: req.Turbo}
+
+	resp := &AutoplayResponse{Balance: gameState.Balance, StopReason: AutoplayStopSpinsDone}
+
+	limit := facade.autoplayLimit(gameState, req.Spins)
+
+	var deadline time.Time
+	if onRound == nil {
+		deadline = time.Now().Add(MaxAutoplayBatchDuration)
+	}
+
+	for i := 0; i < limit; i++ {
+		if reason, stop := facade.autoplayCanContinue(ctx, gameState, deadline); stop {
+			resp.StopReason = reason
+
+			break
+		}
+
+		// the started round is finished even if the client is gone
+		record, err := facade.playRound(context.WithoutCancel(ctx), gameState, wagerReq, mode, metaData)
+		if err != nil {
+			_, err = betUnknown(err)
+
+			if resp.Played == 0 {
+				return nil, err
+			}
+
+			resp.StopReason, resp.Error = AutoplayStopError, err.Error()
+
+			break
+		}
+
+		round := &AutoplayRound{
+			Index:    i,
+			Wager:    record.Wager,
+			Award:    record.FinalAward,
+			FreeSpin: record.IsPFR,
+			State:    gameState.ToWagerState(),
+		}
+
+		resp.add(round)
+
+		if onRound == nil {
+			resp.Rounds = append(resp.Rounds, round)
+		} else if err = onRound(round); err != nil {
+			resp.StopReason, resp.Error = AutoplayStopError, err.Error()
+
+			break
+		}
+
+		if reason, stop := req.Stop.check(resp, record); stop {
+			resp.StopReason = reason
+
+			break
+		}
+	}
+
+	return resp, nil
+}
+
+// autoplayLimit is the number of the spins the autoplay can play in one request.
+func (facade *Facade) autoplayLimit(gameState *entities.GameState, requested int) int {
+	return facade.gamingSrv.AutoplayLimit(gameState, min(requested, MaxAutoplaySpins))
+}
+
+// autoplayCanContinue waits for the minimum spin duration of the jurisdiction and checks the next round can be played
+// before the deadline, zero deadline means no limit.
+func (facade *Facade) autoplayCanContinue(ctx context.Context, gameState *entities.GameState, deadline time.Time) (string, bool) {
+	delay := facade.gamingSrv.Delay(gameState)
+
+	if !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+		return AutoplayStopTimeLimit, true
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+
+	if ctx.Err() != nil {
+		return AutoplayStopCancelled, true
+	}
+
+	return "", false
+}
+
+func (c AutoplayStopConditions) check(resp *AutoplayResponse, record *entities.HistoryRecord) (string, bool) {
+	switch {
+	case c.LossLimit > 0 && resp.Spent-resp.Award >= c.LossLimit:
+		return AutoplayStopLossLimit, true
+	case c.SingleWinLimit > 0 && record.FinalAward >= c.SingleWinLimit:
+		return AutoplayStopWinLimit, true
+	case c.OnBonus && record.Spin.BonusTriggered():
+		return AutoplayStopBonus, true
+	case c.BalanceBelow > 0 && resp.Balance < c.BalanceBelow,
+		c.BalanceAbove > 0 && resp.Balance >= c.BalanceAbove:
+		return AutoplayStopBalance, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,121 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/constants"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/services"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// bonusSpin is the spin which only reports the bonus trigger.
+type bonusSpin struct {
+	engine.Spin
+	triggered bool
+}
+
+func (s bonusSpin) BonusTriggered() bool {
+	return s.triggered
+}
+
+func TestAutoplayStopConditions_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions AutoplayStopConditions
+		resp       AutoplayResponse
+		award      int64
+		bonus      bool
+		want       string
+	}{
+		{name: "no conditions", resp: AutoplayResponse{Spent: 1000, Balance: 10}, award: 5000, bonus: true},
+		{name: "loss limit", conditions: AutoplayStopConditions{LossLimit: 500}, resp: AutoplayResponse{Spent: 1000, Award: 500}, want: AutoplayStopLossLimit},
+		{name: "loss under limit", conditions: AutoplayStopConditions{LossLimit: 500}, resp: AutoplayResponse{Spent: 1000, Award: 501}},
+		{name: "single win", conditions: AutoplayStopConditions{SingleWinLimit: 1000}, award: 1000, want: AutoplayStopWinLimit},
+		{name: "bonus", conditions: AutoplayStopConditions{OnBonus: true}, bonus: true, want: AutoplayStopBonus},
+		{name: "balance below", conditions: AutoplayStopConditions{BalanceBelow: 100}, resp: AutoplayResponse{Balance: 99}, want: AutoplayStopBalance},
+		{name: "balance above", conditions: AutoplayStopConditions{BalanceAbove: 100}, resp: AutoplayResponse{Balance: 100}, want: AutoplayStopBalance},
+		{name: "balance in range", conditions: AutoplayStopConditions{BalanceBelow: 10, BalanceAbove: 100}, resp: AutoplayResponse{Balance: 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &entities.HistoryRecord{FinalAward: tt.award, Spin: bonusSpin{triggered: tt.bonus}}
+
+			reason, stop := tt.conditions.check(&tt.resp, record)
+			require.Equal(t, tt.want, reason)
+			require.Equal(t, tt.want != "", stop)
+		})
+	}
+}
+
+func TestAutoplayResponse_Add(t *testing.T) {
+	resp := &AutoplayResponse{}
+
+	resp.add(&AutoplayRound{Wager: 100, Award: 50, State: &entities.WagerGameState{Balance: 950}})
+	// the free spins are not charged
+	resp.add(&AutoplayRound{Wager: 100, FreeSpin: true, State: &entities.WagerGameState{Balance: 950}})
+	resp.add(&AutoplayRound{Wager: 100, Award: 30, FreeSpin: true, State: &entities.WagerGameState{Balance: 980}})
+
+	require.Equal(t, &AutoplayResponse{Played: 3, Spent: 100, Award: 80, Balance: 980}, resp)
+
+	// the lost free spins do not count toward the loss limit
+	reason, _ := AutoplayStopConditions{LossLimit: 100}.check(resp, &entities.HistoryRecord{Spin: bonusSpin{}})
+	require.Empty(t, reason)
+}
+
+func TestFacade_AutoplayCanContinue(t *testing.T) {
+	gamingSrv := services.NewResponsibleGamingService(&services.ResponsibleGamingConfig{
+		Jurisdictions: map[string]*services.JurisdictionPolicy{"uk": {MinSpinDuration: time.Hour}},
+	})
+	facade := &Facade{boot: &engine.Bootstrap{}, gamingSrv: gamingSrv}
+
+	gameState := &entities.GameState{SessionToken: uuid.New()}
+
+	reason, stop := facade.autoplayCanContinue(context.Background(), gameState, time.Time{})
+	require.False(t, stop)
+	require.Empty(t, reason)
+
+	reason, _ = facade.autoplayCanContinue(context.Background(), gameState, time.Now().Add(-time.Second))
+	require.Equal(t, AutoplayStopTimeLimit, reason)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reason, _ = facade.autoplayCanContinue(ctx, gameState, time.Time{})
+	require.Equal(t, AutoplayStopCancelled, reason)
+
+	// the minimum spin duration does not fit the batch, the autoplay stops without waiting
+	ukState := &entities.GameState{SessionToken: uuid.New(), Jurisdiction: "uk"}
+	reservation, err := gamingSrv.Reserve(ukState, 100, services.PlayMode{Autoplay: true})
+	require.NoError(t, err)
+	gamingSrv.Settle(reservation, 0)
+
+	reason, _ = facade.autoplayCanContinue(context.Background(), ukState, time.Now().Add(time.Minute))
+	require.Equal(t, AutoplayStopTimeLimit, reason)
+
+	// the disconnected client stops the waiting
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	reason, _ = facade.autoplayCanContinue(ctx, ukState, time.Time{})
+	require.Equal(t, AutoplayStopCancelled, reason)
+}
+
+func TestFacade_Autoplay_SequentialRestoring(t *testing.T) {
+	v, err := validator.New(&constants.Config{})
+	require.NoError(t, err)
+
+	facade := &Facade{boot: &engine.Bootstrap{HistoryHandlingType: engine.SequentialRestoring}, validationEngine: v}
+
+	_, err = facade.Autoplay(context.Background(), map[string]interface{}{}, &entities.PlayerMetaData{
+		IP: "127.0.0.1", UserAgent: "test", Host: "https://example.com",
+	}, nil)
+	require.ErrorIs(t, err, errs.ErrAutoplayIsNotSupported)
+}
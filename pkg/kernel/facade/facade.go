@@ -20,11 +20,17 @@ type Facade struct {
 	freeSpinSrv      *services.FreeSpinService
 	historySrv       *services.HistoryService
 	cheatsSrv        *services.CheatsService
+	idempotencySrv   *services.IdempotencyService
+	roundSrv         *services.RoundService
+	gamingSrv        *services.ResponsibleGamingService
+	stateCache       services.GameStateCache
 }
 
 func NewFacade(validationEngine *validator.Validator,
 	gameFlowSrv *services.GameFlowService, historySrv *services.HistoryService,
-	freeSpinSrv *services.FreeSpinService, cheatsSrv *services.CheatsService) *Facade {
+	freeSpinSrv *services.FreeSpinService, cheatsSrv *services.CheatsService,
+	idempotencySrv *services.IdempotencyService, roundSrv *services.RoundService,
+	gamingSrv *services.ResponsibleGamingService, stateCache services.GameStateCache) *Facade {
 	return &Facade{
 		validationEngine: validationEngine,
 		boot:             engine.GetFromContainer(),
@@ -32,6 +38,10 @@ func NewFacade(validationEngine *validator.Validator,
 		freeSpinSrv:      freeSpinSrv,
 		historySrv:       historySrv,
 		cheatsSrv:        cheatsSrv,
+		idempotencySrv:   idempotencySrv,
+		roundSrv:         roundSrv,
+		gamingSrv:        gamingSrv,
+		stateCache:       stateCache,
 	}
 }
 
@@ -46,10 +56,19 @@ func (facade *Facade) InitState(ctx context.Context, payload interface{}) (*enti
 		return nil, err
 	}
 
+	// the rounds of the previous session are saved before the restoring, so the player sees them
+	if err = facade.roundSrv.Recover(ctx, gs); err != nil {
+		zap.S().Errorf("can not recover rounds: %v", err)
+	}
+
 	if err = facade.restoreGameState(ctx, gs); err != nil && !errors.Is(err, errs.ErrHistoryRecordNotFound) {
 		return nil, err
 	}
 
+	facade.stateCache.Set(gs)
+
+	gs.RealityCheck = facade.gamingSrv.RealityCheck(gs)
+
 	return gs.Compute(), nil
 }
 
@@ -63,7 +82,66 @@ func (facade *Facade) Wager(ctx context.Context, payload interface{}, metaData *
 		return nil, err
 	}
 
-	gameState, err := facade.gameFlowSrv.GameState(ctx, req.SessionToken)
+	if req.IdempotencyKey == "" {
+		state, err := facade.wager(ctx, req, metaData)
+		_, err = betUnknown(err)
+
+		return state, err
+	}
+
+	state, ok, err := facade.idempotencySrv.Begin(req.SessionToken, req.IdempotencyKey, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		return state, nil
+	}
+
+	state, err = facade.wager(ctx, req, metaData)
+
+	// the key of the request which can be charged is blocked until the round is recovered,
+	// the key of the request failed before the bet is released for the retry
+	roundID, err := betUnknown(err)
+	if roundID != uuid.Nil {
+		facade.idempotencySrv.Block(req.SessionToken, req.IdempotencyKey, roundID)
+	} else {
+		facade.idempotencySrv.Done(req.SessionToken, req.IdempotencyKey, state)
+	}
+
+	return state, err
+}
+
+// betUnknown returns the round of the bet with the unknown result and the error for the client.
+func betUnknown(err error) (uuid.UUID, error) {
+	var unknown *services.BetUnknownError
+	if errors.As(err, &unknown) {
+		return unknown.RoundID, unknown.Err
+	}
+
+	return uuid.Nil, err
+}
+
+func (facade *Facade) wager(ctx context.Context, req WagerRequest, metaData *entities.PlayerMetaData) (*entities.WagerGameState, error) {
+	gameState, err := facade.wagerState(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = facade.playRound(ctx, gameState, req, services.PlayMode{Turbo: req.Turbo}, metaData); err != nil {
+		return nil, err
+	}
+
+	return gameState.ToWagerState(), nil
+}
+
+// sessionState returns the restored game state of the session from the cache or loads it.
+func (facade *Facade) sessionState(ctx context.Context, sessionToken string) (*entities.GameState, error) {
+	if gameState, ok := facade.stateCache.Get(sessionToken); ok {
+		return gameState, nil
+	}
+
+	gameState, err := facade.gameFlowSrv.GameState(ctx, sessionToken)
 	if err != nil {
 		return nil, err
 	}
@@ -72,11 +150,22 @@ func (facade *Facade) Wager(ctx context.Context, payload interface{}, metaData *
 		return nil, err
 	}
 
+	facade.stateCache.Set(gameState)
+
+	return gameState, nil
+}
+
+// wagerState returns the restored game state of the session and checks that the player can wager.
+func (facade *Facade) wagerState(ctx context.Context, req WagerRequest) (*entities.GameState, error) {
+	gameState, err := facade.sessionState(ctx, req.SessionToken)
+	if err != nil {
+		return nil, err
+	}
+
 	if facade.boot.HistoryHandlingType == engine.SequentialRestoring {
 		lr, ok := gameState.GameResults.Last()
 		if ok {
-			isShown := lr.RestoringIndexes.IsShown(lr.Spin)
-			if !isShown {
+			if !facade.lastSpinIsShown(gameState) {
 				return nil, errs.ErrLastSpinWasNotShown
 			}
 
@@ -90,18 +179,63 @@ func (facade *Facade) Wager(ctx context.Context, payload interface{}, metaData *
 		}
 	}
 
-	gameState, record, err := facade.gameFlowSrv.Wager(ctx, gameState, req.FreeSpinID, req.Wager, req.EngineParams, gameState.MinWager)
+	return gameState, nil
+}
+
+// playRound checks the responsible gaming policy, plays the round on the game state and saves it to the history.
+func (facade *Facade) playRound(ctx context.Context, gameState *entities.GameState, req WagerRequest, mode services.PlayMode,
+	metaData *entities.PlayerMetaData) (*entities.HistoryRecord, error) {
+	// free spins are paid by the operator
+	var stake int64
+
+	if req.FreeSpinID == "" {
+		// the limits are checked against the paid price of the buy bonus or the ante bet
+		purchase, err := facade.gameFlowSrv.Price(gameState, req.Wager, req.EngineParams, false)
+		if err != nil {
+			return nil, err
+		}
+
+		stake = purchase.Total
+	}
+
+	reservation, err := facade.gamingSrv.Reserve(gameState, stake, mode)
 	if err != nil {
 		return nil, err
 	}
 
-	if facade.boot.HistoryHandlingType != engine.NoHistory {
-		if err = facade.historySrv.Create(ctx, record, metaData); err != nil {
-			zap.S().Error(err)
+	_, record, err := facade.gameFlowSrv.Wager(ctx, gameState, req.FreeSpinID, req.Wager, req.EngineParams, gameState.MinWager)
+	if err != nil {
+		facade.stateCache.Delete(req.SessionToken)
+
+		// the bet with the unknown result is counted by the limits
+		if roundID, _ := betUnknown(err); roundID != uuid.Nil {
+			facade.gamingSrv.Settle(reservation, 0)
+		} else {
+			facade.gamingSrv.Cancel(reservation)
 		}
+
+		return nil, err
 	}
 
-	return gameState.ToWagerState(), nil
+	facade.stateCache.Set(gameState)
+	facade.gamingSrv.Settle(reservation, record.FinalAward)
+
+	if err = facade.roundSrv.Confirm(ctx, record, metaData); err != nil {
+		zap.S().Error(err)
+	}
+
+	return record, nil
+}
+
+// lastSpinIsShown reports whether the client has shown the last spin, the spin of the sequential restoring
+// must be shown before the next wager.
+func (facade *Facade) lastSpinIsShown(gameState *entities.GameState) bool {
+	lr, ok := gameState.GameResults.Last()
+	if !ok {
+		return true
+	}
+
+	return lr.RestoringIndexes.IsShown(lr.Spin)
 }
 
 func (facade *Facade) GambleAnyWin(ctx context.Context, payload interface{}, metaData *entities.PlayerMetaData) (*entities.WagerGameState, error) {
@@ -118,21 +252,33 @@ func (facade *Facade) GambleAnyWin(ctx context.Context, payload interface{}, met
 		return nil, err
 	}
 
-	gameState, err := facade.gameFlowSrv.GameState(ctx, req.SessionToken)
+	gameState, err := facade.sessionState(ctx, req.SessionToken)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = facade.restoreGameState(ctx, gameState); err != nil {
+	lgr, ok := gameState.GameResults.Last()
+	if !ok {
+		return nil, errs.ErrHistoryRecordNotFound
+	}
+
+	// the gambled win is the stake of the gamble, the lost gamble is the loss of the session
+	reservation, err := facade.gamingSrv.Reserve(gameState, lgr.GambleStake(), services.PlayMode{Gamble: true})
+	if err != nil {
 		return nil, err
 	}
 
 	gameState, record, err := facade.gameFlowSrv.GambleAnyWin(ctx, gameState, req.EngineParams)
-
 	if err != nil {
+		facade.stateCache.Delete(req.SessionToken)
+		facade.gamingSrv.Cancel(reservation)
+
 		return nil, err
 	}
 
+	facade.stateCache.Set(gameState)
+	facade.gamingSrv.Settle(reservation, record.Spin.GetGamble().Award())
+
 	if err = facade.historySrv.UpdateRecord(ctx, record, metaData); err != nil {
 		zap.S().Error(err)
 	}
@@ -150,21 +296,20 @@ func (facade *Facade) KeepGenerating(ctx context.Context, payload interface{}, m
 		return nil, err
 	}
 
-	gameState, err := facade.gameFlowSrv.GameState(ctx, req.SessionToken)
+	gameState, err := facade.sessionState(ctx, req.SessionToken)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = facade.restoreGameState(ctx, gameState); err != nil {
-		return nil, err
-	}
-
 	gameState, record, err := facade.gameFlowSrv.KeepGenerating(ctx, gameState, req.EngineParams)
-
 	if err != nil {
+		facade.stateCache.Delete(req.SessionToken)
+
 		return nil, err
 	}
 
+	facade.stateCache.Set(gameState)
+
 	if err = facade.historySrv.UpdateRecord(ctx, record, metaData); err != nil {
 		zap.S().Error(err)
 	}
@@ -178,7 +323,7 @@ func (facade *Facade) Paginate(ctx context.Context, payload interface{}) (*entit
 		return nil, err
 	}
 
-	gameState, err := facade.gameFlowSrv.GameState(context.Background(), req.SessionToken)
+	gameState, err := facade.lordState(context.Background(), req.SessionToken)
 	if err != nil {
 		return nil, err
 	}
@@ -196,6 +341,9 @@ func (facade *Facade) UpdateSpinIndexes(ctx context.Context, payload interface{}
 		return err
 	}
 
+	// the shown spins are not restored, the cached results are outdated
+	defer facade.stateCache.Delete(req.SessionToken)
+
 	if facade.boot.HistoryHandlingType == engine.ParallelRestoring {
 		uuidValue, err := uuid.Parse(req.RecordID)
 		if err != nil {
@@ -206,7 +354,7 @@ func (facade *Facade) UpdateSpinIndexes(ctx context.Context, payload interface{}
 	}
 
 	if facade.boot.HistoryHandlingType == engine.SequentialRestoring {
-		gs, err := facade.gameFlowSrv.GameState(ctx, req.SessionToken)
+		gs, err := facade.lordState(ctx, req.SessionToken)
 		if err != nil {
 			return err
 		}
@@ -290,6 +438,16 @@ func (facade *Facade) AddCheat(_ context.Context, payload interface{}) error {
 	return nil
 }
 
+// lordState returns the cached game state of the session or loads it without the restoring,
+// it is enough for the fields of the session.
+func (facade *Facade) lordState(ctx context.Context, sessionToken string) (*entities.GameState, error) {
+	if gameState, ok := facade.stateCache.Get(sessionToken); ok {
+		return gameState, nil
+	}
+
+	return facade.gameFlowSrv.GameState(ctx, sessionToken)
+}
+
 func (facade *Facade) validatePlayerMetadata(playerMetadata *entities.PlayerMetaData) error {
 	if err := facade.validationEngine.ValidateStruct(playerMetadata); err != nil {
 		return errs.NewInternalValidationError(err)
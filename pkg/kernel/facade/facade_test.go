@@ -0,0 +1,190 @@
+package facade
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/constants"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/services"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/validator"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/overlord"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var testMetaData = &entities.PlayerMetaData{IP: "127.0.0.1", UserAgent: "test", Host: "https://example.com"}
+
+// gambleSpin is the won spin which can be gambled.
+type gambleSpin struct {
+	Base    int64          `json:"base"`
+	Wagered int64          `json:"wager"`
+	Gambles *engine.Gamble `json:"gambles"`
+}
+
+func (s *gambleSpin) BaseAward() int64                       { return s.Base }
+func (s *gambleSpin) BonusAward() int64                      { return 0 }
+func (s *gambleSpin) OriginalWager() int64                   { return s.Wagered }
+func (s *gambleSpin) Wager() int64                           { return s.Wagered }
+func (s *gambleSpin) BonusTriggered() bool                   { return false }
+func (s *gambleSpin) CanGamble(engine.RestoringIndexes) bool { return true }
+
+func (s *gambleSpin) GetGamble() *engine.Gamble {
+	if s.Gambles == nil {
+		s.Gambles = &engine.Gamble{}
+	}
+
+	return s.Gambles
+}
+
+func (s *gambleSpin) DeepCopy() engine.Spin {
+	cp := *s
+
+	if s.Gambles != nil {
+		gambles := make(engine.Gamble, 0, s.Gambles.Len())
+		for _, item := range *s.Gambles {
+			gi := *item
+			gambles = append(gambles, &gi)
+		}
+
+		cp.Gambles = &gambles
+	}
+
+	return &cp
+}
+
+// shownIndexes are the restoring indexes of the shown spin.
+type shownIndexes struct{}
+
+func (shownIndexes) IsShown(engine.Spin) bool { return true }
+func (shownIndexes) Update(interface{}) error { return nil }
+
+// gambleFactory restores the gamble spins, the tests do not generate the spins.
+type gambleFactory struct {
+	engine.SpinFactory
+}
+
+func (gambleFactory) UnmarshalJSONSpin(bytes []byte) (engine.Spin, error) {
+	spin := &gambleSpin{}
+
+	return spin, json.Unmarshal(bytes, spin)
+}
+
+func (gambleFactory) UnmarshalJSONRestoringIndexes([]byte) (engine.RestoringIndexes, error) {
+	return shownIndexes{}, nil
+}
+
+func (gambleFactory) GetRngClient() rng.Client {
+	return rng.NewSeededClient(1)
+}
+
+// testLord places every bet on the balance of 1000.
+type testLord struct {
+	overlord.Client
+}
+
+func (testLord) AtomicBet(_ context.Context, _, _, _ string, wager, award int64, _ bool) (*overlord.AtomicBetOut, error) {
+	return &overlord.AtomicBetOut{TransactionId: uuid.NewString(), Balance: 1000 - wager + award}, nil
+}
+
+// testHistory accepts every update of the records.
+type testHistory struct {
+	history.Client
+}
+
+func (testHistory) Update(context.Context, *history.SpinIn) error {
+	return nil
+}
+
+// newCachingFacade returns the facade of the game without history which caches the game states.
+func newCachingFacade(t *testing.T) *Facade {
+	boot := &engine.Bootstrap{HistoryHandlingType: engine.NoHistory, GambleAnyWinFeature: true, SpinFactory: gambleFactory{}}
+	engine.PutInContainer(boot)
+
+	v, err := validator.New(&constants.Config{})
+	require.NoError(t, err)
+
+	cache := services.NewGameStateCache(&services.GameStateCacheConfig{TTL: time.Minute}, boot.SpinFactory)
+	t.Cleanup(func() { _ = cache.Close() })
+
+	return &Facade{
+		validationEngine: v,
+		boot:             boot,
+		gameFlowSrv:      services.NewGameFlowService(testLord{}, nil, services.NewCheatsService(), nil, nil),
+		gamingSrv:        services.NewResponsibleGamingService(nil),
+		historySrv:       services.NewHistoryService(testHistory{}),
+		stateCache:       cache,
+	}
+}
+
+func TestFacade_Wager_ErrorInvalidatesState(t *testing.T) {
+	facade := newCachingFacade(t)
+
+	gameState := &entities.GameState{SessionToken: uuid.New(), Balance: 1000, WagerLevels: []int64{100}}
+	token := gameState.SessionToken.String()
+
+	facade.stateCache.Set(gameState)
+
+	// the wager is not in the levels, the state of the failed round is reloaded by the next request
+	_, err := facade.Wager(context.Background(), map[string]interface{}{"session_token": token, "wager": 50}, testMetaData)
+	require.ErrorAs(t, err, &errs.InternalValidationError{})
+
+	_, ok := facade.stateCache.Get(token)
+	require.False(t, ok)
+}
+
+func TestFacade_GambleAnyWin_SessionLossLimit(t *testing.T) {
+	facade := newCachingFacade(t)
+	facade.gamingSrv = services.NewResponsibleGamingService(&services.ResponsibleGamingConfig{
+		Default: &services.JurisdictionPolicy{SessionLossLimit: 1000},
+	})
+
+	gameState := &entities.GameState{
+		SessionToken:   uuid.New(),
+		Balance:        1500,
+		GambleDoubleUp: 5,
+		GameResults:    entities.GameResults{{ID: uuid.New(), Spin: &gambleSpin{Base: 600, Wagered: 100}, RestoringIndexes: shownIndexes{}}},
+	}
+	token := gameState.SessionToken.String()
+
+	facade.stateCache.Set(gameState)
+
+	// the red is drawn, the black is lost
+	services.NewCheatsService().Add(token, map[string]interface{}{"gamble_pick": 1})
+
+	state, err := facade.GambleAnyWin(context.Background(), map[string]interface{}{
+		"session_token": token, "engine_params": map[string]interface{}{"gamble_pick": 0},
+	}, testMetaData)
+	require.NoError(t, err)
+	require.Equal(t, int64(400), state.Balance)
+
+	// the lost stake of 600 is counted by the loss limit of the session
+	_, err = facade.gamingSrv.Reserve(gameState, 401, services.PlayMode{})
+	require.ErrorIs(t, err, errs.ErrSessionLossLimitReached)
+
+	_, err = facade.gamingSrv.Reserve(gameState, 400, services.PlayMode{})
+	require.NoError(t, err)
+}
+
+func TestFacade_UpdateSpinIndexes_InvalidatesState(t *testing.T) {
+	facade := newCachingFacade(t)
+
+	gameState := &entities.GameState{SessionToken: uuid.New()}
+	token := gameState.SessionToken.String()
+
+	facade.stateCache.Set(gameState)
+
+	err := facade.UpdateSpinIndexes(context.Background(), map[string]interface{}{
+		"session_token": token, "restoring_indexes": map[string]interface{}{"shown": true},
+	}, testMetaData)
+	require.ErrorIs(t, err, errs.ErrUpdatingIsNotAllowed)
+
+	_, ok := facade.stateCache.Get(token)
+	require.False(t, ok)
+}
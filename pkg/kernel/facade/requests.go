@@ -11,6 +11,29 @@ type WagerRequest struct {
 	Wager        int64       `json:"wager" form:"wager"`
 	FreeSpinID   string      `json:"freespin_id"`
 	EngineParams interface{} `json:"engine_params"`
+	// Turbo is the fast play mode of the client, it is forbidden in some jurisdictions.
+	Turbo bool `json:"turbo"`
+	// IdempotencyKey makes the retries of the request return the original result, it is unique per session.
+	IdempotencyKey string `json:"idempotency_key" validate:"omitempty,max=128"`
+}
+
+type AutoplayRequest struct {
+	SessionToken string                 `json:"session_token" form:"session_token" validate:"required"`
+	Wager        int64                  `json:"wager" form:"wager"`
+	FreeSpinID   string                 `json:"freespin_id"`
+	EngineParams interface{}            `json:"engine_params"`
+	Spins        int                    `json:"spins" validate:"required,gt=0"`
+	Turbo        bool                   `json:"turbo"`
+	Stop         AutoplayStopConditions `json:"stop"`
+}
+
+// AutoplayStopConditions stop the autoplay after the round, zero values are not checked.
+type AutoplayStopConditions struct {
+	LossLimit      int64 `json:"loss_limit" validate:"gte=0"`
+	SingleWinLimit int64 `json:"single_win_limit" validate:"gte=0"`
+	OnBonus        bool  `json:"on_bonus"`
+	BalanceBelow   int64 `json:"balance_below" validate:"gte=0"`
+	BalanceAbove   int64 `json:"balance_above" validate:"gte=0"`
 }
 
 type GambleAnyWinRequest struct {
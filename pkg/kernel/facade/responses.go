@@ -11,3 +11,34 @@ type GetFreeSpinsResponse struct {
 type GetFreeSpinsWithIntegratorBetResponse struct {
 	FreeSpins map[string][]*entities.FreeSpin `json:"freespins"`
 }
+
+type AutoplayRound struct {
+	Index int   `json:"index"`
+	Wager int64 `json:"wager"`
+	Award int64 `json:"award"`
+	// FreeSpin is set if the round is played with the free spin, its wager is not charged.
+	FreeSpin bool                     `json:"free_spin,omitempty"`
+	State    *entities.WagerGameState `json:"state"`
+}
+
+type AutoplayResponse struct {
+	// Rounds are empty if they are streamed to the client.
+	Rounds     []*AutoplayRound `json:"rounds,omitempty"`
+	Played     int              `json:"played"`
+	Spent      int64            `json:"spent"`
+	Award      int64            `json:"award"`
+	Balance    int64            `json:"balance"`
+	StopReason string           `json:"stop_reason"`
+	Error      string           `json:"error,omitempty"`
+}
+
+func (r *AutoplayResponse) add(round *AutoplayRound) {
+	r.Played++
+
+	if !round.FreeSpin {
+		r.Spent += round.Wager
+	}
+
+	r.Award += round.Award
+	r.Balance = round.State.Balance
+}
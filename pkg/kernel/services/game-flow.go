@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"fmt"
 
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
@@ -11,7 +10,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
-	"golang.org/x/exp/slog"
 )
 
 type GameFlowService struct {
@@ -19,14 +17,19 @@ type GameFlowService struct {
 	boot       *engine.Bootstrap
 	historySrv *HistoryService
 	cheatsSrv  *CheatsService
+	jackpotSrv *JackpotService
+	roundSrv   *RoundService
 }
 
-func NewGameFlowService(lord overlord.Client, historySrv *HistoryService, cheatsSrv *CheatsService) *GameFlowService {
+func NewGameFlowService(lord overlord.Client, historySrv *HistoryService, cheatsSrv *CheatsService,
+	jackpotSrv *JackpotService, roundSrv *RoundService) *GameFlowService {
 	return &GameFlowService{
 		lord:       lord,
 		boot:       engine.GetFromContainer(),
 		historySrv: historySrv,
 		cheatsSrv:  cheatsSrv,
+		jackpotSrv: jackpotSrv,
+		roundSrv:   roundSrv,
 	}
 }
 
@@ -64,6 +67,21 @@ func (s *GameFlowService) GameState(ctx context.Context, sessionToken string) (*
 		SetBootInfo(s.boot.GetBootInfo()), nil
 }
 
+// Price returns the purchase of the wager with the features of the params allowed for the player.
+func (s *GameFlowService) Price(gameState *entities.GameState, wager int64, params interface{}, isPFR bool) (*engine.Purchase, error) {
+	purchase, err := s.boot.Price(wager, params, engine.PricingRules{
+		Jurisdiction: gameState.Jurisdiction,
+		BuyBonus:     gameState.BuyBonus,
+		DoubleChance: gameState.DoubleChance,
+		IsPFR:        isPFR,
+	})
+	if err != nil {
+		return nil, errs.NewInternalValidationErrorFromString(err.Error())
+	}
+
+	return purchase, nil
+}
+
 func (s *GameFlowService) Wager(ctx context.Context,
 	gameState *entities.GameState, freeSpinID string, wager int64, params interface{}, minWager int64) (
 	*entities.GameState, *entities.HistoryRecord, error,
@@ -93,50 +111,38 @@ func (s *GameFlowService) Wager(ctx context.Context,
 
 	engCtx := s.getEngineContext(ctx, gameState, params)
 
-	var (
-		spin             engine.Spin
-		indexes          engine.RestoringIndexes
-		err              error
-		award            int64
-		roundID          = uuid.New()
-		exceedMultiplier bool
-	)
-
-	const (
-		maxMultiplier = 5000
-		maxAttempts   = 100
-	)
-
-	attempts := 0
-	for {
-		if attempts >= maxAttempts {
-			return nil, nil, fmt.Errorf("maximum number of generation attempts has been exceeded (%d)", maxAttempts)
-		}
+	purchase, err := s.Price(gameState, wager, params, isPFR)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		spin, indexes, err = s.boot.SpinFactory.Generate(engCtx, wager, params)
-		if err != nil {
-			return nil, nil, err
-		}
+	engCtx.Purchase = purchase
 
-		award = engine.TotalAward(spin)
+	jackpots, err := s.jackpotSrv.Requests(ctx, gameState.Game, gameState.Currency, gameState.CurrencyMultiplier, wager)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		if award/wager < int64(maxMultiplier) {
-			break
-		}
+	engCtx.Jackpots = jackpots
 
-		slog.Warn("Win exceeds max multiplier",
-			"attempt", attempts+1,
-			"award", award,
-			"wager", wager,
-			"win_multiplier", float64(award)/float64(wager),
-			"max multiplier", maxMultiplier,
-			"round_id", roundID,
-		)
-		exceedMultiplier = true
-		attempts++
+	roundID := uuid.New()
+
+	spin, indexes, err := s.boot.SpinFactory.Generate(engCtx, wager, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := engine.CheckPurchase(spin, purchase); err != nil {
+		return nil, nil, err
 	}
-	if exceedMultiplier {
-		slog.Warn("Given: ",
+
+	maxWin := entities.ApplyMaxWin(spin, s.boot.MaxWin(wager))
+
+	baseAward, bonusAward := maxWin.Awards(spin)
+	award := baseAward + bonusAward
+
+	if maxWin != nil {
+		zap.S().Infow("max win is reached",
 			"award", award,
 			"wager", wager,
 			"round_id", roundID,
@@ -148,27 +154,60 @@ func (s *GameFlowService) Wager(ctx context.Context,
 		return nil, nil, errs.ErrNotEnoughMoney
 	}
 
+	// jackpots are paid over the max win cap
+	settlement, err := s.jackpotSrv.Settle(ctx, gameState.Game, gameState.Currency, gameState.CurrencyMultiplier, engCtx.Jackpots, spin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	award += settlement.Award()
+
 	var record *entities.HistoryRecord
 
-	bet, err := s.lord.AtomicBet(ctx, gameState.SessionToken.String(), freeSpinID, roundID.String(), spin.Wager(), award, false)
+	round, err := s.roundSrv.Open(ctx, gameState.PendingRecord(spin, indexes, isPFR, maxWin, roundID), freeSpinID, award, settlement)
 	if err != nil {
-		return nil, nil, errs.TranslateOverlordErr(err)
+		s.jackpotSrv.Rollback(ctx, settlement)
+
+		return nil, nil, err
 	}
 
-	if isPFR {
-		record = gameState.SetGeneratedFreeSpin(spin, indexes, isPFR, bet.Balance, roundID)
-	} else {
-		record = gameState.SetGeneratedSpin(spin, indexes, isPFR, bet.Balance, roundID)
+	bet, err := s.lord.AtomicBet(ctx, gameState.SessionToken.String(), freeSpinID, roundID.String(), spin.Wager(), award, false)
+	if err != nil {
+		// the round stays pending if the result of the bet is unknown, its jackpots are rolled back
+		// with it if the reconciled bet is declined
+		if !overlord.IsDeclined(err) {
+			return nil, nil, &BetUnknownError{RoundID: roundID, Err: errs.TranslateOverlordErr(err)}
+		}
+
+		s.roundSrv.Cancel(ctx, round)
+
+		return nil, nil, errs.TranslateOverlordErr(err)
 	}
 
 	transactionID, err := uuid.Parse(bet.TransactionId)
 	if err != nil {
-		// rollback
+		zap.S().Errorf("can not parse uuid %v", bet.TransactionId)
+
+		// the jackpots are rolled back with the round
+		if err = s.roundSrv.Rollback(ctx, round, bet.TransactionId); err != nil {
+			zap.S().Errorf("can not roll back round %v: %v", roundID, err)
+
+			return nil, nil, &BetUnknownError{RoundID: roundID, Err: errs.ErrInternalBadData}
+		}
+
 		return nil, nil, errs.ErrInternalBadData
 	}
 
+	if isPFR {
+		record = gameState.SetGeneratedFreeSpin(spin, indexes, isPFR, maxWin, bet.Balance, roundID)
+	} else {
+		record = gameState.SetGeneratedSpin(spin, indexes, isPFR, maxWin, bet.Balance, roundID)
+	}
+
 	record.SetTransactionID(transactionID)
 
+	s.roundSrv.Paid(ctx, round, record)
+
 	return gameState, record, nil
 }
 
@@ -190,9 +229,14 @@ func (s *GameFlowService) GambleAnyWin(ctx context.Context, gameState *entities.
 
 	gamble := lgr.Spin.GetGamble()
 
-	err := gamble.Play(s.boot.SpinFactory.GetRngClient(), lgr.Spin, params, engCtx.Cheats)
+	err := gamble.PlayWithConfig(s.boot.SpinFactory.GetRngClient(), lgr.Spin, params, engCtx.Cheats,
+		engine.GambleConfig{
+			DoubleUpLimit: int(gameState.GambleDoubleUp),
+			MaxWin:        s.boot.MaxWin(lgr.Spin.OriginalWager()),
+			Stake:         lgr.BaseAward(),
+		})
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, errs.TranslateGambleErr(err)
 	}
 
 	roundID := uuid.NewString()
@@ -204,14 +248,19 @@ func (s *GameFlowService) GambleAnyWin(ctx context.Context, gameState *entities.
 		return nil, nil, errs.TranslateOverlordErr(err)
 	}
 
-	record := gameState.UpdateLastSpin(lgr.Spin, bet.Balance)
-
 	transactionID, err := uuid.Parse(bet.TransactionId)
 	if err != nil {
-		// rollback
+		zap.S().Errorf("can not parse uuid %v", bet.TransactionId)
+
+		if _, err = s.lord.RollbackBet(ctx, gameState.SessionToken.String(), bet.TransactionId); err != nil {
+			zap.S().Errorf("can not roll back gamble %v: %v", roundID, err)
+		}
+
 		return nil, nil, errs.ErrInternalBadData
 	}
 
+	record := gameState.UpdateLastSpin(lgr.Spin, lgr.MaxWin, bet.Balance)
+
 	record.SetTransactionID(transactionID)
 
 	return gameState, record, nil
@@ -225,7 +274,7 @@ func (s *GameFlowService) KeepGenerating(ctx context.Context, gameState *entitie
 		return nil, nil, errs.ErrHistoryRecordNotFound
 	}
 
-	oldSpin := lgr.Spin.DeepCopy()
+	oldAward := lgr.TotalAward()
 
 	spin, ok, err := s.boot.SpinFactory.KeepGenerate(engCtx, params)
 	if err != nil {
@@ -238,7 +287,9 @@ func (s *GameFlowService) KeepGenerating(ctx context.Context, gameState *entitie
 
 	roundID := uuid.NewString()
 
-	award := engine.TotalAward(spin) - engine.TotalAward(oldSpin)
+	maxWin := entities.ApplyMaxWin(spin, s.boot.MaxWin(spin.OriginalWager()))
+
+	award := maxWin.TotalAward(spin) - oldAward
 	if award < 0 {
 		zap.S().Errorf("negative award %v", award)
 
@@ -250,7 +301,7 @@ func (s *GameFlowService) KeepGenerating(ctx context.Context, gameState *entitie
 		return nil, nil, errs.TranslateOverlordErr(err)
 	}
 
-	record := gameState.UpdateLastSpin(spin, bet.Balance)
+	record := gameState.UpdateLastSpin(spin, maxWin, bet.Balance)
 
 	transactionID, err := uuid.Parse(bet.TransactionId)
 	if err != nil {
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"github.com/jellydator/ttlcache/v3"
+	"go.uber.org/zap"
+)
+
+// GameStateCache keeps the restored game states by the session token, so the wager does not load the state
+// from overlord and the history. The entries are replaced by the states with the balance of the bet responses
+// and keep the expiry of the loaded state, so the changes of the balance outside the game are seen
+// at most the ttl after the state is loaded even if the player keeps wagering.
+// The implementations return the copies, the caller can change the state.
+type GameStateCache interface {
+	Get(sessionToken string) (*entities.GameState, bool)
+	Set(gameState *entities.GameState)
+	Delete(sessionToken string)
+	Close() error
+}
+
+type GameStateCacheConfig struct {
+	TTL time.Duration
+}
+
+// NewGameStateCache returns the in-memory cache, the states are not cached without the config.
+// The in-memory cache is for a single server or for the sticky sessions, the other servers do not invalidate it.
+// The spins of the states are copied by their DeepCopy, the restoring indexes by the factory of the game.
+func NewGameStateCache(cfg *GameStateCacheConfig, factory engine.SpinFactory) GameStateCache {
+	if cfg == nil || cfg.TTL <= 0 {
+		return noGameStateCache{}
+	}
+
+	zap.S().Warn("game states are cached in memory, it is safe only for a single server or sticky sessions")
+
+	cache := ttlcache.New[string, *entities.GameState](
+		ttlcache.WithTTL[string, *entities.GameState](cfg.TTL),
+		// the reads do not extend the ttl, Set keeps it
+		ttlcache.WithDisableTouchOnHit[string, *entities.GameState](),
+	)
+
+	go cache.Start()
+
+	return &memoryGameStateCache{cache: cache, factory: factory}
+}
+
+type memoryGameStateCache struct {
+	cache   *ttlcache.Cache[string, *entities.GameState]
+	factory engine.SpinFactory
+}
+
+func (c *memoryGameStateCache) Get(sessionToken string) (*entities.GameState, bool) {
+	item := c.cache.Get(sessionToken)
+	if item == nil {
+		return nil, false
+	}
+
+	gameState, err := item.Value().Clone(c.factory)
+	if err != nil {
+		zap.S().Errorf("can not clone game state: %v", err)
+
+		return nil, false
+	}
+
+	return gameState, true
+}
+
+// Set replaces the entry with the state until the expiry of the entry, the state is cached for the ttl
+// if there is no entry.
+func (c *memoryGameStateCache) Set(gameState *entities.GameState) {
+	key := gameState.SessionToken.String()
+
+	clone, err := gameState.Clone(c.factory)
+	if err != nil {
+		zap.S().Errorf("can not clone game state: %v", err)
+		c.cache.Delete(key)
+
+		return
+	}
+
+	ttl := ttlcache.DefaultTTL
+	if item := c.cache.Get(key); item != nil {
+		if ttl = time.Until(item.ExpiresAt()); ttl <= 0 {
+			c.cache.Delete(key)
+
+			return
+		}
+	}
+
+	c.cache.Set(key, clone, ttl)
+}
+
+func (c *memoryGameStateCache) Delete(sessionToken string) {
+	c.cache.Delete(sessionToken)
+}
+
+func (c *memoryGameStateCache) Close() error {
+	c.cache.Stop()
+
+	return nil
+}
+
+type noGameStateCache struct{}
+
+func (noGameStateCache) Get(string) (*entities.GameState, bool) { return nil, false }
+func (noGameStateCache) Set(*entities.GameState)                {}
+func (noGameStateCache) Delete(string)                          {}
+func (noGameStateCache) Close() error                           { return nil }
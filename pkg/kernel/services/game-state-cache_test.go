@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedState() *entities.GameState {
+	return &entities.GameState{
+		SessionToken: uuid.New(),
+		Balance:      1000,
+		GameResults: entities.GameResults{
+			{ID: uuid.New(), Spin: &testSpin{Base: 100, Window: [][]int{{1, 2}, {3, 4}}}},
+		},
+	}
+}
+
+func TestGameStateCache_Copies(t *testing.T) {
+	cache := NewGameStateCache(&GameStateCacheConfig{TTL: time.Minute}, newTestFactory(1, 1))
+	defer cache.Close()
+
+	gameState := newCachedState()
+	token := gameState.SessionToken.String()
+
+	cache.Set(gameState)
+
+	// the state changed after Set does not change the entry
+	gameState.Balance = 0
+	gameState.GameResults[0].Spin.(*testSpin).Window[0][0] = 9
+
+	got, ok := cache.Get(token)
+	require.True(t, ok)
+	require.Equal(t, int64(1000), got.Balance)
+
+	spin := got.GameResults[0].Spin.(*testSpin)
+	require.Equal(t, [][]int{{1, 2}, {3, 4}}, spin.Window)
+
+	// the mutated result of Get does not change the entry
+	got.Balance = 500
+	got.GameResults[0].ID = uuid.Nil
+	spin.Window[1][1] = 9
+	spin.Base = 0
+	got.GameResults = append(got.GameResults, &entities.GameResult{})
+
+	got, ok = cache.Get(token)
+	require.True(t, ok)
+	require.Equal(t, int64(1000), got.Balance)
+	require.Len(t, got.GameResults, 1)
+	require.NotEqual(t, uuid.Nil, got.GameResults[0].ID)
+	require.Equal(t, &testSpin{Base: 100, Window: [][]int{{1, 2}, {3, 4}}}, got.GameResults[0].Spin)
+}
+
+func TestGameStateCache_Invalidation(t *testing.T) {
+	cache := NewGameStateCache(&GameStateCacheConfig{TTL: 50 * time.Millisecond}, newTestFactory(1, 1))
+	defer cache.Close()
+
+	gameState := newCachedState()
+	token := gameState.SessionToken.String()
+
+	cache.Set(gameState)
+	cache.Delete(token)
+
+	_, ok := cache.Get(token)
+	require.False(t, ok)
+
+	// the entry expires after the ttl
+	cache.Set(gameState)
+
+	_, ok = cache.Get(token)
+	require.True(t, ok)
+
+	require.Eventually(t, func() bool {
+		_, ok := cache.Get(token)
+
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGameStateCache_SetKeepsExpiry(t *testing.T) {
+	cache := NewGameStateCache(&GameStateCacheConfig{TTL: 100 * time.Millisecond}, newTestFactory(1, 1))
+	defer cache.Close()
+
+	gameState := newCachedState()
+	token := gameState.SessionToken.String()
+
+	cache.Set(gameState)
+	loadedAt := time.Now()
+
+	// the wagers of the active player replace the entry, it still expires the ttl after the state is loaded
+	require.Eventually(t, func() bool {
+		got, ok := cache.Get(token)
+		if ok {
+			got.Balance -= 100
+			cache.Set(got)
+		}
+
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+
+	require.Less(t, time.Since(loadedAt), 500*time.Millisecond)
+
+	// the state loaded after the expiry is cached for the ttl again
+	cache.Set(gameState)
+
+	got, ok := cache.Get(token)
+	require.True(t, ok)
+	require.Equal(t, int64(1000), got.Balance)
+}
+
+func TestGameStateCache_Disabled(t *testing.T) {
+	for _, cfg := range []*GameStateCacheConfig{nil, {}} {
+		cache := NewGameStateCache(cfg, newTestFactory(1, 1))
+		cache.Set(newCachedState())
+
+		require.Equal(t, noGameStateCache{}, cache)
+	}
+}
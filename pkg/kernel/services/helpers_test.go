@@ -0,0 +1,103 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+)
+
+// testSpin is a minimal spin for the tests of the services.
+type testSpin struct {
+	Base     int64               `json:"base"`
+	Bonus    int64               `json:"bonus"`
+	Wagered  int64               `json:"wager"`
+	Window   [][]int             `json:"window"`
+	Triggers bool                `json:"triggers"`
+	Jackpots []engine.JackpotWin `json:"jackpots"`
+	Gambles  *engine.Gamble      `json:"gambles"`
+}
+
+func (s *testSpin) BaseAward() int64                         { return s.Base }
+func (s *testSpin) BonusAward() int64                        { return s.Bonus }
+func (s *testSpin) OriginalWager() int64                     { return s.Wagered }
+func (s *testSpin) Wager() int64                             { return s.Wagered }
+func (s *testSpin) BonusTriggered() bool                     { return s.Triggers }
+func (s *testSpin) CanGamble(_ engine.RestoringIndexes) bool { return true }
+func (s *testSpin) JackpotWins() []engine.JackpotWin         { return s.Jackpots }
+func (s *testSpin) SetJackpotWins(wins []engine.JackpotWin)  { s.Jackpots = wins }
+
+func (s *testSpin) GetGamble() *engine.Gamble {
+	if s.Gambles == nil {
+		s.Gambles = &engine.Gamble{}
+	}
+
+	return s.Gambles
+}
+
+func (s *testSpin) DeepCopy() engine.Spin {
+	cp := *s
+	cp.Window = make([][]int, len(s.Window))
+
+	for i := range s.Window {
+		cp.Window[i] = append([]int(nil), s.Window[i]...)
+	}
+
+	cp.Jackpots = append([]engine.JackpotWin(nil), s.Jackpots...)
+
+	return &cp
+}
+
+var errTestGenerate = errors.New("test generate error")
+
+// testFactory generates spins with the base award of wager * [0, multipliers) drawn from the rng client.
+type testFactory struct {
+	rand        rng.Client
+	multipliers uint64
+	failAt      int64 // the number of the Generate call that fails, 0 means never
+	calls       *atomic.Int64
+}
+
+func newTestFactory(seed uint64, multipliers uint64) *testFactory {
+	return &testFactory{rand: rng.NewSeededClient(seed), multipliers: multipliers, calls: new(atomic.Int64)}
+}
+
+func (f *testFactory) Generate(_ engine.Context, wager int64, _ interface{}) (engine.Spin, engine.RestoringIndexes, error) {
+	if call := f.calls.Add(1); call == f.failAt {
+		return nil, nil, errTestGenerate
+	}
+
+	multiplier, err := f.rand.Rand(f.multipliers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &testSpin{Base: int64(multiplier) * wager, Wagered: wager, Triggers: multiplier == f.multipliers-1}, nil, nil
+}
+
+func (f *testFactory) KeepGenerate(ctx engine.Context, _ interface{}) (engine.Spin, bool, error) {
+	return ctx.LastSpin, false, nil
+}
+
+func (f *testFactory) UnmarshalJSONSpin(bytes []byte) (engine.Spin, error) {
+	spin := &testSpin{}
+
+	return spin, json.Unmarshal(bytes, spin)
+}
+
+func (f *testFactory) UnmarshalJSONRestoringIndexes(_ []byte) (engine.RestoringIndexes, error) {
+	return nil, nil
+}
+
+func (f *testFactory) GetRngClient() rng.Client {
+	return f.rand
+}
+
+func (f *testFactory) WithRngClient(client rng.Client) engine.SpinFactory {
+	cp := *f
+	cp.rand = client
+
+	return &cp
+}
@@ -53,6 +53,10 @@ func (s *HistoryService) Create(ctx context.Context, record *entities.HistoryRec
 		return err
 	}
 
+	return s.create(ctx, spinIn)
+}
+
+func (s *HistoryService) create(ctx context.Context, spinIn *history.SpinIn) error {
 	return errs.TranslateHistoryErr(s.historyClient.Create(ctx, spinIn))
 }
 
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"github.com/google/uuid"
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// DefaultIdempotencyTTL is the ttl of the results without IdempotencyConfig.
+const DefaultIdempotencyTTL = 10 * time.Minute
+
+// IdempotencyConfig is optional, the results are kept for DefaultIdempotencyTTL without it.
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+type idempotentResult struct {
+	fingerprint [sha256.Size]byte
+	state       *entities.WagerGameState
+	// roundID is the round with the unknown bet result, the key is blocked until the round is recovered
+	roundID uuid.UUID
+}
+
+// IdempotencyService keeps the results of the wagers with idempotency keys for the ttl,
+// so the retried request gets the original result and the player is not charged twice.
+// The results are kept in memory of the server.
+type IdempotencyService struct {
+	cache *ttlcache.Cache[string, *idempotentResult]
+
+	mu       sync.Mutex
+	inFlight map[string][sha256.Size]byte
+	blocked  map[uuid.UUID]string // round id -> key
+}
+
+func NewIdempotencyService(cfg *IdempotencyConfig) *IdempotencyService {
+	ttl := DefaultIdempotencyTTL
+	if cfg != nil && cfg.TTL > 0 {
+		ttl = cfg.TTL
+	}
+
+	s := &IdempotencyService{
+		cache: ttlcache.New[string, *idempotentResult](
+			ttlcache.WithTTL[string, *idempotentResult](ttl),
+			// the retries do not extend the ttl of the result
+			ttlcache.WithDisableTouchOnHit[string, *idempotentResult](),
+		),
+		inFlight: map[string][sha256.Size]byte{},
+		blocked:  map[uuid.UUID]string{},
+	}
+
+	s.cache.OnEviction(func(_ context.Context, _ ttlcache.EvictionReason, item *ttlcache.Item[string, *idempotentResult]) {
+		if item.Value().state != nil {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.blocked[item.Value().roundID] == item.Key() {
+			delete(s.blocked, item.Value().roundID)
+		}
+	})
+
+	return s
+}
+
+func (s *IdempotencyService) Start() {
+	go s.cache.Start()
+}
+
+func (s *IdempotencyService) Stop() {
+	s.cache.Stop()
+}
+
+// Begin returns the cached result of the key, otherwise it marks the key as in flight until Done or Block is called.
+// request is any value that identifies the request, the key can not be reused with another request.
+func (s *IdempotencyService) Begin(session, key string, request interface{}) (*entities.WagerGameState, bool, error) {
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cacheKey := session + ":" + key
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item := s.cache.Get(cacheKey); item != nil {
+		if item.Value().fingerprint != fingerprint {
+			return nil, false, errs.ErrIdempotencyKeyReused
+		}
+
+		// the bet of the blocked key can be placed, the result is known after the recovery of the round
+		if item.Value().state == nil {
+			return nil, false, errs.ErrRequestInProgress
+		}
+
+		return item.Value().state, true, nil
+	}
+
+	if inFlight, ok := s.inFlight[cacheKey]; ok {
+		if inFlight != fingerprint {
+			return nil, false, errs.ErrIdempotencyKeyReused
+		}
+
+		return nil, false, errs.ErrRequestInProgress
+	}
+
+	s.inFlight[cacheKey] = fingerprint
+
+	return nil, false, nil
+}
+
+// Done caches the result of the key, nil state means the request failed before the bet
+// and can be retried with the same key.
+func (s *IdempotencyService) Done(session, key string, state *entities.WagerGameState) {
+	cacheKey := session + ":" + key
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fingerprint, ok := s.inFlight[cacheKey]
+	if !ok {
+		return
+	}
+
+	delete(s.inFlight, cacheKey)
+
+	if state != nil {
+		s.cache.Set(cacheKey, &idempotentResult{fingerprint: fingerprint, state: state}, ttlcache.DefaultTTL)
+	}
+}
+
+// Block keeps the key of the request with the unknown bet result for the ttl, the retries get ErrRequestInProgress.
+// The key is released by Release when the round is rolled back.
+func (s *IdempotencyService) Block(session, key string, roundID uuid.UUID) {
+	cacheKey := session + ":" + key
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fingerprint, ok := s.inFlight[cacheKey]
+	if !ok {
+		return
+	}
+
+	delete(s.inFlight, cacheKey)
+
+	s.blocked[roundID] = cacheKey
+	s.cache.Set(cacheKey, &idempotentResult{fingerprint: fingerprint, roundID: roundID}, ttlcache.DefaultTTL)
+}
+
+// Release unblocks the key of the rolled back round, so the request can be retried.
+func (s *IdempotencyService) Release(roundID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cacheKey, ok := s.blocked[roundID]
+	if !ok {
+		return
+	}
+
+	delete(s.blocked, roundID)
+	s.cache.Delete(cacheKey)
+}
+
+func requestFingerprint(request interface{}) ([sha256.Size]byte, error) {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(b), nil
+}
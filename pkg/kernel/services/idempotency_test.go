@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type idempotentRequest struct {
+	Wager int64
+}
+
+func TestIdempotencyService_Replay(t *testing.T) {
+	s := NewIdempotencyService(nil)
+
+	state, ok, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Nil(t, state)
+
+	want := &entities.WagerGameState{Balance: 900}
+	s.Done("session", "key", want)
+
+	// the retry gets the original result
+	state, ok, err = s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Same(t, want, state)
+
+	// the keys are scoped by the session
+	_, ok, err = s.Begin("other", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestIdempotencyService_InFlight(t *testing.T) {
+	s := NewIdempotencyService(nil)
+
+	_, _, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+
+	_, _, err = s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.ErrorIs(t, err, errs.ErrRequestInProgress)
+
+	// the request failed before the bet can be retried with the same key
+	s.Done("session", "key", nil)
+
+	_, ok, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestIdempotencyService_KeyReused(t *testing.T) {
+	s := NewIdempotencyService(nil)
+
+	_, _, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+
+	_, _, err = s.Begin("session", "key", idempotentRequest{Wager: 200})
+	require.ErrorIs(t, err, errs.ErrIdempotencyKeyReused)
+
+	s.Done("session", "key", &entities.WagerGameState{})
+
+	_, _, err = s.Begin("session", "key", idempotentRequest{Wager: 200})
+	require.ErrorIs(t, err, errs.ErrIdempotencyKeyReused)
+}
+
+func TestIdempotencyService_Block(t *testing.T) {
+	s := NewIdempotencyService(nil)
+	roundID := uuid.New()
+
+	_, _, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+
+	s.Block("session", "key", roundID)
+
+	// the bet can be placed, the retry must not be charged again
+	_, _, err = s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.ErrorIs(t, err, errs.ErrRequestInProgress)
+
+	_, _, err = s.Begin("session", "key", idempotentRequest{Wager: 200})
+	require.ErrorIs(t, err, errs.ErrIdempotencyKeyReused)
+
+	// the other rounds do not release the key
+	s.Release(uuid.New())
+
+	_, _, err = s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.ErrorIs(t, err, errs.ErrRequestInProgress)
+
+	// the rolled back round releases the key
+	s.Release(roundID)
+
+	_, ok, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestIdempotencyService_TTL(t *testing.T) {
+	s := NewIdempotencyService(&IdempotencyConfig{TTL: 50 * time.Millisecond})
+	s.Start()
+	defer s.Stop()
+
+	roundID := uuid.New()
+
+	_, _, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+
+	s.Block("session", "key", roundID)
+
+	require.Eventually(t, func() bool {
+		_, _, err := s.Begin("session", "key", idempotentRequest{Wager: 100})
+
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// the evicted round is forgotten
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return len(s.blocked) == 0
+	}, time.Second, 10*time.Millisecond)
+}
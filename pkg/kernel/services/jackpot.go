@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/jackpot"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+type JackpotService struct {
+	storage jackpot.Storage
+	tiers   map[string]engine.JackpotTier
+}
+
+func NewJackpotService(storage jackpot.Storage) *JackpotService {
+	return &JackpotService{
+		storage: storage,
+		tiers: lo.KeyBy(engine.GetFromContainer().Jackpots, func(item engine.JackpotTier) string {
+			return item.Name
+		}),
+	}
+}
+
+// JackpotSettlement is the result of the round for jackpots, keep it to roll back the failed bet.
+type JackpotSettlement struct {
+	Game               string              `json:"game"`
+	Currency           string              `json:"currency"`
+	CurrencyMultiplier int64               `json:"currency_multiplier"`
+	Contributions      map[string]int64    `json:"contributions"` // tier -> amount
+	Wins               []engine.JackpotWin `json:"wins"`
+}
+
+func (s *JackpotSettlement) Award() int64 {
+	if s == nil {
+		return 0
+	}
+
+	return lo.SumBy(s.Wins, func(item engine.JackpotWin) int64 { return item.Award })
+}
+
+// Requests returns the jackpots for the engine context with current values, nil if the game has no jackpots.
+func (s *JackpotService) Requests(ctx context.Context, game, currency string, currencyMultiplier, wager int64) (*engine.JackpotRequests, error) {
+	if len(s.tiers) == 0 {
+		return nil, nil
+	}
+
+	requests := &engine.JackpotRequests{Values: map[string]int64{}}
+
+	for name, tier := range s.tiers {
+		if tier.Type == engine.FixedJackpot {
+			requests.Values[name] = wager * tier.Multiplier
+
+			continue
+		}
+
+		pool, err := s.storage.Get(ctx, s.key(game, currency, name), seed(tier, currencyMultiplier))
+		if err != nil {
+			return nil, err
+		}
+
+		requests.Values[name] = pool.Value()
+	}
+
+	return requests, nil
+}
+
+// Settle contributes the wager to the progressive pools and pays out the jackpots requested by the engine.
+// The wins are set to the spin, so they are saved in the history with it.
+func (s *JackpotService) Settle(
+	ctx context.Context, game, currency string, currencyMultiplier int64, requests *engine.JackpotRequests, spin engine.Spin,
+) (*JackpotSettlement, error) {
+	if len(s.tiers) == 0 || requests == nil {
+		return nil, nil
+	}
+
+	var winner engine.JackpotWinner
+
+	// every tier is paid once in the round even if the engine requests it several times
+	requested := lo.Uniq(requests.Requested)
+
+	if len(requested) > 0 {
+		var ok bool
+
+		if winner, ok = spin.(engine.JackpotWinner); !ok {
+			return nil, fmt.Errorf("spin %T does not implement engine.JackpotWinner", spin)
+		}
+
+		for _, name := range requested {
+			if _, ok := s.tiers[name]; !ok {
+				return nil, fmt.Errorf("unknown jackpot tier %s", name)
+			}
+		}
+	}
+
+	settlement := &JackpotSettlement{
+		Game:               game,
+		Currency:           currency,
+		CurrencyMultiplier: currencyMultiplier,
+		Contributions:      map[string]int64{},
+	}
+
+	for name, tier := range s.tiers {
+		if tier.Type != engine.ProgressiveJackpot {
+			continue
+		}
+
+		amount := int64(math.Round(float64(spin.Wager()) * tier.Contribution / 100))
+		if amount == 0 {
+			continue
+		}
+
+		if _, err := s.storage.Contribute(ctx, s.key(game, currency, name), seed(tier, currencyMultiplier), amount); err != nil {
+			s.Rollback(ctx, settlement)
+
+			return nil, err
+		}
+
+		settlement.Contributions[name] = amount
+	}
+
+	for _, name := range requested {
+		tier := s.tiers[name]
+		award := requests.Values[name]
+
+		if tier.Type == engine.ProgressiveJackpot {
+			var err error
+
+			if award, err = s.storage.Take(ctx, s.key(game, currency, name), seed(tier, currencyMultiplier)); err != nil {
+				s.Rollback(ctx, settlement)
+
+				return nil, err
+			}
+		}
+
+		settlement.Wins = append(settlement.Wins, engine.JackpotWin{Tier: name, Award: award})
+	}
+
+	if winner != nil {
+		winner.SetJackpotWins(settlement.Wins)
+	}
+
+	return settlement, nil
+}
+
+// Rollback returns the contributions and the won progressive pools back, it is called when the bet is failed.
+func (s *JackpotService) Rollback(ctx context.Context, settlement *JackpotSettlement) {
+	if settlement == nil {
+		return
+	}
+
+	for name, amount := range settlement.Contributions {
+		key := s.key(settlement.Game, settlement.Currency, name)
+
+		if _, err := s.storage.Contribute(ctx, key, seed(s.tiers[name], settlement.CurrencyMultiplier), -amount); err != nil {
+			zap.S().Errorf("can not roll back jackpot contribution %v: %v", key, err)
+		}
+	}
+
+	for _, win := range settlement.Wins {
+		tier := s.tiers[win.Tier]
+		if tier.Type != engine.ProgressiveJackpot {
+			continue
+		}
+
+		// the pool was reset to the seed, return the won amount over it
+		key := s.key(settlement.Game, settlement.Currency, win.Tier)
+		tierSeed := seed(tier, settlement.CurrencyMultiplier)
+
+		if _, err := s.storage.Contribute(ctx, key, tierSeed, win.Award-tierSeed); err != nil {
+			zap.S().Errorf("can not roll back jackpot win %v: %v", key, err)
+		}
+	}
+}
+
+// seed returns the seed of the tier in the currency of the pool.
+func seed(tier engine.JackpotTier, currencyMultiplier int64) int64 {
+	return tier.Seed * max(currencyMultiplier, 1)
+}
+
+func (s *JackpotService) key(game, currency, tier string) jackpot.Key {
+	return jackpot.Key{Game: game, Currency: currency, Tier: tier}
+}
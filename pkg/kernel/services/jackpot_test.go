@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/jackpot"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJackpotService() *JackpotService {
+	return &JackpotService{
+		storage: jackpot.NewMemoryStorage(),
+		tiers: map[string]engine.JackpotTier{
+			"mini":  {Name: "mini", Type: engine.FixedJackpot, Multiplier: 10},
+			"grand": {Name: "grand", Type: engine.ProgressiveJackpot, Seed: 1000, Contribution: 1},
+		},
+	}
+}
+
+func grandValue(t *testing.T, s *JackpotService) int64 {
+	pool, err := s.storage.Get(context.Background(), s.key("game", "usd", "grand"), 1000)
+	require.NoError(t, err)
+
+	return pool.Value()
+}
+
+func TestJackpotService_Requests(t *testing.T) {
+	s := newTestJackpotService()
+
+	requests, err := s.Requests(context.Background(), "game", "usd", 1, 100)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"mini": 1000, "grand": 1000}, requests.Values)
+
+	requests, err = (&JackpotService{}).Requests(context.Background(), "game", "usd", 1, 100)
+	require.NoError(t, err)
+	require.Nil(t, requests)
+}
+
+func TestJackpotService_Contribution(t *testing.T) {
+	ctx := context.Background()
+	s := newTestJackpotService()
+
+	requests, err := s.Requests(ctx, "game", "usd", 1, 500)
+	require.NoError(t, err)
+
+	settlement, err := s.Settle(ctx, "game", "usd", 1, requests, &testSpin{Wagered: 500})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"grand": 5}, settlement.Contributions)
+	require.Zero(t, settlement.Award())
+	require.Equal(t, int64(1005), grandValue(t, s))
+}
+
+func TestJackpotService_Settle(t *testing.T) {
+	ctx := context.Background()
+	s := newTestJackpotService()
+
+	_, err := s.storage.Contribute(ctx, s.key("game", "usd", "grand"), 1000, 495)
+	require.NoError(t, err)
+
+	requests, err := s.Requests(ctx, "game", "usd", 1, 500)
+	require.NoError(t, err)
+
+	// the duplicated tiers are paid once
+	requests.Requested = []string{"grand", "mini", "grand"}
+
+	spin := &testSpin{Wagered: 500}
+
+	settlement, err := s.Settle(ctx, "game", "usd", 1, requests, spin)
+	require.NoError(t, err)
+	require.Equal(t, []engine.JackpotWin{{Tier: "grand", Award: 1500}, {Tier: "mini", Award: 5000}}, settlement.Wins)
+	require.Equal(t, settlement.Wins, spin.JackpotWins())
+	require.Equal(t, int64(6500), settlement.Award())
+	require.Equal(t, int64(1000), grandValue(t, s))
+}
+
+func TestJackpotService_Rollback(t *testing.T) {
+	ctx := context.Background()
+	s := newTestJackpotService()
+
+	_, err := s.storage.Contribute(ctx, s.key("game", "usd", "grand"), 1000, 95)
+	require.NoError(t, err)
+
+	requests, err := s.Requests(ctx, "game", "usd", 1, 500)
+	require.NoError(t, err)
+
+	requests.Requested = []string{"grand"}
+
+	settlement, err := s.Settle(ctx, "game", "usd", 1, requests, &testSpin{Wagered: 500})
+	require.NoError(t, err)
+	require.Equal(t, int64(1100), settlement.Award())
+
+	s.Rollback(ctx, settlement)
+
+	// the pool is back to the value before the round: the contribution is returned and the win is restored
+	require.Equal(t, int64(1095), grandValue(t, s))
+}
+
+func TestJackpotService_SettleErrors(t *testing.T) {
+	ctx := context.Background()
+	s := newTestJackpotService()
+
+	requests := &engine.JackpotRequests{Values: map[string]int64{}, Requested: []string{"major"}}
+
+	_, err := s.Settle(ctx, "game", "usd", 1, requests, &testSpin{Wagered: 500})
+	require.ErrorContains(t, err, "unknown jackpot tier major")
+	require.Equal(t, int64(1000), grandValue(t, s))
+
+	settlement, err := s.Settle(ctx, "game", "usd", 1, nil, &testSpin{Wagered: 500})
+	require.NoError(t, err)
+	require.Nil(t, settlement)
+}
+
+func TestJackpotService_CurrencyMultiplier(t *testing.T) {
+	ctx := context.Background()
+	s := newTestJackpotService()
+
+	usd, err := s.Requests(ctx, "game", "usd", 1, 500)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), usd.Values["grand"])
+
+	// the pool of the 1000x currency starts from the seed in its units
+	idr, err := s.Requests(ctx, "game", "idr", 1000, 500000)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000000), idr.Values["grand"])
+
+	_, err = s.storage.Contribute(ctx, s.key("game", "idr", "grand"), 1000000, 95000)
+	require.NoError(t, err)
+
+	idr.Requested = []string{"grand"}
+
+	settlement, err := s.Settle(ctx, "game", "idr", 1000, idr, &testSpin{Wagered: 500000})
+	require.NoError(t, err)
+	require.Equal(t, int64(1100000), settlement.Award())
+
+	// the won pool is reset to the scaled seed and restored by the rollback
+	pool, err := s.storage.Get(ctx, s.key("game", "idr", "grand"), 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000000), pool.Value())
+
+	s.Rollback(ctx, settlement)
+
+	pool, err = s.storage.Get(ctx, s.key("game", "idr", "grand"), 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1095000), pool.Value())
+	require.Equal(t, int64(1000), grandValue(t, s))
+}
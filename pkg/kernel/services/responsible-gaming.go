@@ -0,0 +1,263 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// gamingSessionTTL keeps the played sessions, the limits of the session are reset after it.
+const gamingSessionTTL = 24 * time.Hour
+
+type ResponsibleGamingConfig struct {
+	// Default is the policy of the jurisdictions which are not in the Jurisdictions.
+	Default *JurisdictionPolicy
+	// Jurisdictions are the policies by GameState.Jurisdiction, the keys are case-insensitive.
+	Jurisdictions map[string]*JurisdictionPolicy
+}
+
+// JurisdictionPolicy is the set of the responsible gaming limits, zero values mean no limit.
+type JurisdictionPolicy struct {
+	// MinSpinDuration is the minimum time between the spins, for example, 2.5s in the UK.
+	MinSpinDuration time.Duration
+	// MaxStake is the max wager by the currency in the balance units, the currencies are case-insensitive.
+	MaxStake map[string]int64
+	// SessionLossLimit is the max net loss of the session in the balance units.
+	SessionLossLimit     int64
+	RealityCheckInterval time.Duration
+	AutoplayDisabled     bool
+	TurboDisabled        bool
+	MaxAutoplaySpins     int
+}
+
+// PlayMode is the way the player wagers.
+type PlayMode struct {
+	Autoplay bool
+	Turbo    bool
+	// Gamble is the gamble of the last win, it is counted by the loss limit but it is not a spin
+	// and is not delayed by the minimum spin duration.
+	Gamble bool
+}
+
+type gamingSession struct {
+	startedAt  time.Time
+	lastSpinAt time.Time
+	wagered    int64
+	won        int64
+	reserved   int64 // wagers of the rounds in progress
+}
+
+// Reservation is the wager of the round in progress, it is counted by the limits of the session
+// until it is settled or cancelled.
+type Reservation struct {
+	key        string
+	wager      int64
+	spinAt     time.Time
+	prevSpinAt time.Time
+}
+
+// ResponsibleGamingService applies the responsible gaming policy of the jurisdiction of the player.
+// The sessions are kept in memory, so the limits are applied per server and reset on restart.
+type ResponsibleGamingService struct {
+	cfg      *ResponsibleGamingConfig
+	sessions *ttlcache.Cache[string, *gamingSession]
+	mu       sync.Mutex
+}
+
+func NewResponsibleGamingService(cfg *ResponsibleGamingConfig) *ResponsibleGamingService {
+	if cfg == nil {
+		cfg = &ResponsibleGamingConfig{}
+	}
+
+	jurisdictions := make(map[string]*JurisdictionPolicy, len(cfg.Jurisdictions))
+	for name, policy := range cfg.Jurisdictions {
+		jurisdictions[strings.ToLower(name)] = normalizePolicy(policy)
+	}
+
+	return &ResponsibleGamingService{
+		cfg: &ResponsibleGamingConfig{Default: normalizePolicy(cfg.Default), Jurisdictions: jurisdictions},
+		sessions: ttlcache.New[string, *gamingSession](
+			ttlcache.WithTTL[string, *gamingSession](gamingSessionTTL),
+		),
+	}
+}
+
+func (s *ResponsibleGamingService) Start() {
+	go s.sessions.Start()
+}
+
+func (s *ResponsibleGamingService) Stop() {
+	s.sessions.Stop()
+}
+
+// Policy returns the policy of the jurisdiction, the empty policy if it is not configured.
+func (s *ResponsibleGamingService) Policy(jurisdiction string) JurisdictionPolicy {
+	// viper lowercases the keys of the config
+	if policy, ok := s.cfg.Jurisdictions[strings.ToLower(jurisdiction)]; ok && policy != nil {
+		return *policy
+	}
+
+	if s.cfg.Default != nil {
+		return *s.cfg.Default
+	}
+
+	return JurisdictionPolicy{}
+}
+
+// Reserve checks the wager against the policy of the player and reserves it in the session under one lock,
+// so the concurrent wagers of the session can not exceed the limits together.
+// The reservation must be settled by Settle or released by Cancel.
+func (s *ResponsibleGamingService) Reserve(gameState *entities.GameState, wager int64, mode PlayMode) (*Reservation, error) {
+	policy := s.Policy(gameState.Jurisdiction)
+
+	if mode.Autoplay && policy.AutoplayDisabled {
+		return nil, errs.ErrAutoplayNotAllowed
+	}
+
+	if mode.Turbo && policy.TurboDisabled {
+		return nil, errs.ErrTurboNotAllowed
+	}
+
+	if maxStake, ok := policy.MaxStake[strings.ToLower(gameState.Currency)]; ok && wager > maxStake {
+		return nil, errs.ErrStakeLimitExceeded
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	key := gameState.SessionToken.String()
+
+	session := &gamingSession{startedAt: now}
+	if item := s.sessions.Get(key); item != nil {
+		cp := *item.Value()
+		session = &cp
+	}
+
+	if policy.SessionLossLimit > 0 && session.wagered-session.won+session.reserved+wager > policy.SessionLossLimit {
+		return nil, errs.ErrSessionLossLimitReached
+	}
+
+	reservation := &Reservation{key: key, wager: wager}
+
+	if !mode.Gamble {
+		if !session.lastSpinAt.IsZero() && now.Sub(session.lastSpinAt) < policy.MinSpinDuration {
+			return nil, errs.ErrSpinTooFast
+		}
+
+		reservation.spinAt, reservation.prevSpinAt = now, session.lastSpinAt
+		session.lastSpinAt = now
+	}
+
+	session.reserved += wager
+
+	s.sessions.Set(key, session, ttlcache.DefaultTTL)
+
+	return reservation, nil
+}
+
+// Delay is the time left until the next spin is allowed by the minimum spin duration.
+func (s *ResponsibleGamingService) Delay(gameState *entities.GameState) time.Duration {
+	session := s.session(gameState)
+	if session.lastSpinAt.IsZero() {
+		return 0
+	}
+
+	return max(0, s.Policy(gameState.Jurisdiction).MinSpinDuration-time.Since(session.lastSpinAt))
+}
+
+// Settle adds the played round of the reservation with the award to the session of the player.
+func (s *ResponsibleGamingService) Settle(reservation *Reservation, award int64) {
+	s.update(reservation, func(session *gamingSession) {
+		session.wagered += reservation.wager
+		session.won += award
+	})
+}
+
+// Cancel releases the reservation of the round which is not played, the next spin is not delayed by it.
+func (s *ResponsibleGamingService) Cancel(reservation *Reservation) {
+	s.update(reservation, func(session *gamingSession) {
+		if session.lastSpinAt.Equal(reservation.spinAt) {
+			session.lastSpinAt = reservation.prevSpinAt
+		}
+	})
+}
+
+func (s *ResponsibleGamingService) update(reservation *Reservation, update func(session *gamingSession)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.sessions.Get(reservation.key)
+	if item == nil {
+		return
+	}
+
+	session := *item.Value()
+	session.reserved -= reservation.wager
+	update(&session)
+
+	s.sessions.Set(reservation.key, &session, ttlcache.DefaultTTL)
+}
+
+// RealityCheck returns the reality check of the session, nil if the jurisdiction does not require it.
+func (s *ResponsibleGamingService) RealityCheck(gameState *entities.GameState) *entities.RealityCheck {
+	interval := s.Policy(gameState.Jurisdiction).RealityCheckInterval
+	if interval <= 0 {
+		return nil
+	}
+
+	session := s.session(gameState)
+
+	check := &entities.RealityCheck{
+		Interval: int64(interval / time.Second),
+		Wagered:  session.wagered,
+		Won:      session.won,
+	}
+
+	if !session.startedAt.IsZero() {
+		check.Elapsed = int64(time.Since(session.startedAt) / time.Second)
+	}
+
+	return check
+}
+
+// AutoplayLimit is the number of the spins of the autoplay allowed by the policy.
+func (s *ResponsibleGamingService) AutoplayLimit(gameState *entities.GameState, requested int) int {
+	if limit := s.Policy(gameState.Jurisdiction).MaxAutoplaySpins; limit > 0 {
+		return min(requested, limit)
+	}
+
+	return requested
+}
+
+// normalizePolicy lowercases the currencies of the policy, viper lowercases the keys of the config.
+func normalizePolicy(policy *JurisdictionPolicy) *JurisdictionPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	cp := *policy
+	cp.MaxStake = make(map[string]int64, len(policy.MaxStake))
+
+	for currency, stake := range policy.MaxStake {
+		cp.MaxStake[strings.ToLower(currency)] = stake
+	}
+
+	return &cp
+}
+
+// session returns the copy of the session of the player, the empty session if the player has not played yet.
+func (s *ResponsibleGamingService) session(gameState *entities.GameState) gamingSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item := s.sessions.Get(gameState.SessionToken.String()); item != nil {
+		return *item.Value()
+	}
+
+	return gamingSession{}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGamingService(policy *JurisdictionPolicy) *ResponsibleGamingService {
+	return NewResponsibleGamingService(&ResponsibleGamingConfig{
+		Jurisdictions: map[string]*JurisdictionPolicy{"UK": policy},
+	})
+}
+
+func newTestGamingState() *entities.GameState {
+	return &entities.GameState{SessionToken: uuid.New(), Jurisdiction: "uk", Currency: "gbp"}
+}
+
+func TestResponsibleGamingService_Reserve(t *testing.T) {
+	s := newTestGamingService(&JurisdictionPolicy{
+		MaxStake:         map[string]int64{"GBP": 500},
+		AutoplayDisabled: true,
+		TurboDisabled:    true,
+	})
+	gameState := newTestGamingState()
+
+	_, err := s.Reserve(gameState, 500, PlayMode{Autoplay: true})
+	require.ErrorIs(t, err, errs.ErrAutoplayNotAllowed)
+
+	_, err = s.Reserve(gameState, 500, PlayMode{Turbo: true})
+	require.ErrorIs(t, err, errs.ErrTurboNotAllowed)
+
+	_, err = s.Reserve(gameState, 501, PlayMode{})
+	require.ErrorIs(t, err, errs.ErrStakeLimitExceeded)
+
+	_, err = s.Reserve(gameState, 500, PlayMode{})
+	require.NoError(t, err)
+
+	// the other jurisdictions are not limited
+	_, err = s.Reserve(&entities.GameState{SessionToken: uuid.New(), Currency: "gbp"}, 10000, PlayMode{Autoplay: true})
+	require.NoError(t, err)
+}
+
+func TestResponsibleGamingService_SessionLossLimit(t *testing.T) {
+	s := newTestGamingService(&JurisdictionPolicy{SessionLossLimit: 1000})
+	gameState := newTestGamingState()
+
+	first, err := s.Reserve(gameState, 600, PlayMode{})
+	require.NoError(t, err)
+
+	// the round in progress is counted by the limit
+	_, err = s.Reserve(gameState, 600, PlayMode{})
+	require.ErrorIs(t, err, errs.ErrSessionLossLimitReached)
+
+	s.Settle(first, 500)
+
+	// the net loss is 100
+	second, err := s.Reserve(gameState, 900, PlayMode{})
+	require.NoError(t, err)
+
+	s.Cancel(second)
+
+	_, err = s.Reserve(gameState, 901, PlayMode{})
+	require.ErrorIs(t, err, errs.ErrSessionLossLimitReached)
+
+	check := newTestGamingService(&JurisdictionPolicy{RealityCheckInterval: time.Hour})
+	reservation, err := check.Reserve(gameState, 600, PlayMode{})
+	require.NoError(t, err)
+	check.Settle(reservation, 500)
+
+	require.Equal(t, &entities.RealityCheck{Interval: 3600, Wagered: 600, Won: 500}, check.RealityCheck(gameState))
+}
+
+func TestResponsibleGamingService_Reserve_Concurrent(t *testing.T) {
+	s := newTestGamingService(&JurisdictionPolicy{SessionLossLimit: 1000})
+	gameState := newTestGamingState()
+
+	var (
+		wg       sync.WaitGroup
+		reserved atomic.Int64
+	)
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := s.Reserve(gameState, 100, PlayMode{}); err == nil {
+				reserved.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// the concurrent wagers of the session do not exceed the limit together
+	require.Equal(t, int64(10), reserved.Load())
+}
+
+func TestResponsibleGamingService_MinSpinDuration(t *testing.T) {
+	s := newTestGamingService(&JurisdictionPolicy{MinSpinDuration: time.Hour})
+	gameState := newTestGamingState()
+
+	require.Zero(t, s.Delay(gameState))
+
+	first, err := s.Reserve(gameState, 100, PlayMode{})
+	require.NoError(t, err)
+
+	_, err = s.Reserve(gameState, 100, PlayMode{})
+	require.ErrorIs(t, err, errs.ErrSpinTooFast)
+	require.Greater(t, s.Delay(gameState), 59*time.Minute)
+
+	// the cancelled round does not delay the next spin
+	s.Cancel(first)
+	require.Zero(t, s.Delay(gameState))
+
+	played, err := s.Reserve(gameState, 100, PlayMode{})
+	require.NoError(t, err)
+
+	s.Settle(played, 0)
+
+	_, err = s.Reserve(gameState, 100, PlayMode{})
+	require.ErrorIs(t, err, errs.ErrSpinTooFast)
+}
+
+func TestResponsibleGamingService_Gamble(t *testing.T) {
+	s := newTestGamingService(&JurisdictionPolicy{MinSpinDuration: time.Hour, SessionLossLimit: 1000})
+	gameState := newTestGamingState()
+
+	spin, err := s.Reserve(gameState, 100, PlayMode{})
+	require.NoError(t, err)
+	s.Settle(spin, 500)
+
+	delay := s.Delay(gameState)
+
+	// the gamble right after the spin is not delayed and does not delay the next spin
+	gamble, err := s.Reserve(gameState, 500, PlayMode{Gamble: true})
+	require.NoError(t, err)
+	require.LessOrEqual(t, s.Delay(gameState), delay)
+
+	// the lost gamble takes the won award back, the net loss is the wager of the spin
+	s.Settle(gamble, 0)
+
+	_, err = s.Reserve(gameState, 901, PlayMode{Gamble: true})
+	require.ErrorIs(t, err, errs.ErrSessionLossLimitReached)
+
+	_, err = s.Reserve(gameState, 900, PlayMode{Gamble: true})
+	require.NoError(t, err)
+}
+
+func TestGameFlowService_Price(t *testing.T) {
+	s := &GameFlowService{boot: &engine.Bootstrap{
+		AnteBetMultiplier: 125,
+		BuyOptions:        []engine.BuyOption{{Name: "free_spins", Price: 10000, Bonus: "fs"}},
+	}}
+	gameState := &entities.GameState{BuyBonus: true}
+
+	purchase, err := s.Price(gameState, 100, map[string]interface{}{"ante": true}, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(125), purchase.Total)
+
+	purchase, err = s.Price(gameState, 100, map[string]interface{}{"buy": "free_spins"}, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(10000), purchase.Total)
+
+	_, err = s.Price(&entities.GameState{}, 100, map[string]interface{}{"buy": "free_spins"}, false)
+	require.ErrorAs(t, err, &errs.InternalValidationError{})
+}
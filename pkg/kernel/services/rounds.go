@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/overlord"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rounds"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// roundRecoveryDelay skips the rounds which are just updated, they can be still processed by the wager.
+const roundRecoveryDelay = time.Minute
+
+// BetUnknownError is returned by the wager when the bet of the round can be placed by overlord,
+// the result is known only after the recovery of the round.
+type BetUnknownError struct {
+	RoundID uuid.UUID
+	Err     error
+}
+
+func (e *BetUnknownError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BetUnknownError) Unwrap() error {
+	return e.Err
+}
+
+// RoundService keeps the write-ahead records of the rounds, so the paid round is saved to the history
+// or rolled back even if the server fails in the middle of the wager.
+type RoundService struct {
+	storage    rounds.Storage
+	lord       overlord.Client
+	historySrv *HistoryService
+	// idempotencySrv releases the idempotency keys of the rolled back rounds
+	idempotencySrv *IdempotencyService
+	// jackpotSrv rolls back the jackpots of the declined and rolled back rounds
+	jackpotSrv *JackpotService
+	boot       *engine.Bootstrap
+}
+
+func NewRoundService(storage rounds.Storage, lord overlord.Client, historySrv *HistoryService,
+	idempotencySrv *IdempotencyService, jackpotSrv *JackpotService) *RoundService {
+	return &RoundService{
+		storage:        storage,
+		lord:           lord,
+		historySrv:     historySrv,
+		idempotencySrv: idempotencySrv,
+		jackpotSrv:     jackpotSrv,
+		boot:           engine.GetFromContainer(),
+	}
+}
+
+// Open saves the pending round with the record of the spin, it must be called before the bet.
+// award is the award of the bet including the jackpots, settlement is the jackpots of the round, nil if there are none.
+func (s *RoundService) Open(ctx context.Context, record *entities.HistoryRecord, freeSpinID string, award int64,
+	settlement *JackpotSettlement,
+) (*rounds.Round, error) {
+	data, err := s.marshalRecord(record, &entities.PlayerMetaData{})
+	if err != nil {
+		return nil, err
+	}
+
+	var jackpots []byte
+
+	if settlement != nil {
+		if jackpots, err = json.Marshal(settlement); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
+	round := &rounds.Round{
+		ID:           record.ID,
+		UserID:       record.UserID,
+		Game:         record.Game,
+		SessionToken: record.SessionToken.String(),
+		Status:       rounds.StatusPending,
+		Wager:        record.Wager,
+		Award:        award,
+		FreeSpinID:   freeSpinID,
+		Record:       data,
+		NoHistory:    s.boot.HistoryHandlingType == engine.NoHistory,
+		Jackpots:     jackpots,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err = s.storage.Save(ctx, round); err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// Cancel deletes the round of the declined or rolled back bet and rolls back its jackpots.
+// The jackpots are rolled back once: the round which is not deleted is cancelled again by the recovery.
+func (s *RoundService) Cancel(ctx context.Context, round *rounds.Round) {
+	if err := s.storage.Delete(ctx, round.ID); err != nil {
+		zap.S().Errorf("can not delete round %v: %v", round.ID, err)
+
+		return
+	}
+
+	if len(round.Jackpots) == 0 {
+		return
+	}
+
+	settlement := &JackpotSettlement{}
+	if err := json.Unmarshal(round.Jackpots, settlement); err != nil {
+		zap.S().Errorf("can not unmarshal jackpots of round %v: %v", round.ID, err)
+
+		return
+	}
+
+	s.jackpotSrv.Rollback(ctx, settlement)
+}
+
+// Paid marks the round as placed with the record of the bet, the record is saved on the next InitState
+// if the server fails before Confirm.
+func (s *RoundService) Paid(ctx context.Context, round *rounds.Round, record *entities.HistoryRecord) {
+	data, err := s.marshalRecord(record, &entities.PlayerMetaData{})
+	if err != nil {
+		zap.S().Errorf("can not marshal record of round %v: %v", round.ID, err)
+	}
+
+	s.mark(ctx, round, rounds.StatusPaid, record.TransactionID.String(), data)
+}
+
+func (s *RoundService) mark(ctx context.Context, round *rounds.Round, status rounds.Status, transactionID string, record []byte) {
+	round.Status = status
+	round.TransactionID = transactionID
+	round.Record = record
+	round.UpdatedAt = time.Now()
+
+	if err := s.storage.Save(ctx, round); err != nil {
+		zap.S().Errorf("can not mark round %v as %v: %v", round.ID, status, err)
+	}
+}
+
+// Rollback compensates the placed bet of the round which can not be finished.
+// The round is kept and rolled back on the next InitState if overlord fails.
+func (s *RoundService) Rollback(ctx context.Context, round *rounds.Round, transactionID string) error {
+	s.mark(ctx, round, rounds.StatusRollback, transactionID, nil)
+
+	if _, err := s.lord.RollbackBet(ctx, round.SessionToken, round.TransactionID); err != nil {
+		return errs.TranslateOverlordErr(err)
+	}
+
+	zap.S().Infow("round is rolled back", "round_id", round.ID, "transaction_id", round.TransactionID)
+
+	s.Cancel(ctx, round)
+
+	// the wager of the blocked idempotency key can be retried
+	s.idempotencySrv.Release(round.ID)
+
+	return nil
+}
+
+// Confirm saves the record of the paid round to the history and deletes the round.
+// The round is kept with the record if the history fails, it is saved on the next InitState.
+func (s *RoundService) Confirm(ctx context.Context, record *entities.HistoryRecord, metaData *entities.PlayerMetaData) error {
+	if s.boot.HistoryHandlingType == engine.NoHistory {
+		return s.storage.Delete(ctx, record.ID)
+	}
+
+	spinIn, err := record.ToHistoryServiceIn(metaData)
+	if err != nil {
+		return err
+	}
+
+	if err = s.historySrv.create(ctx, spinIn); err == nil {
+		return s.storage.Delete(ctx, record.ID)
+	}
+
+	data, marshalErr := proto.Marshal(spinIn)
+	if marshalErr != nil {
+		return errors.Join(err, marshalErr)
+	}
+
+	round := &rounds.Round{
+		ID:            record.ID,
+		UserID:        record.UserID,
+		Game:          record.Game,
+		SessionToken:  record.SessionToken.String(),
+		Status:        rounds.StatusPaid,
+		Wager:         record.Wager,
+		Award:         record.FinalAward,
+		TransactionID: record.TransactionID.String(),
+		Record:        data,
+		CreatedAt:     record.CreatedAt,
+		UpdatedAt:     time.Now(),
+	}
+
+	return errors.Join(err, s.storage.Save(ctx, round))
+}
+
+// Recover finishes the unconfirmed rounds of the player: the records are saved to the history,
+// the paid rounds of the games without history are deleted, the rounds without records are rolled back
+// and the bets of the pending rounds are reconciled.
+// The round which can not be recovered is kept for the next InitState.
+func (s *RoundService) Recover(ctx context.Context, gameState *entities.GameState) error {
+	unconfirmed, err := s.storage.Unconfirmed(ctx, gameState.UserID, gameState.Game)
+	if err != nil {
+		return err
+	}
+
+	for _, round := range unconfirmed {
+		if time.Since(round.UpdatedAt) < roundRecoveryDelay {
+			continue
+		}
+
+		switch {
+		case round.Status == rounds.StatusPending:
+			err = s.reconcile(ctx, gameState, round)
+		case round.Status == rounds.StatusRollback:
+			err = s.Rollback(ctx, round, round.TransactionID)
+		case round.NoHistory:
+			err = s.storage.Delete(ctx, round.ID)
+		case len(round.Record) == 0:
+			err = s.Rollback(ctx, round, round.TransactionID)
+		default:
+			err = s.saveRecord(ctx, round)
+		}
+
+		if err != nil {
+			zap.S().Errorw("can not recover round",
+				"round_id", round.ID,
+				"user_id", round.UserID,
+				"status", round.Status,
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// reconcile repeats the bet of the pending round. Overlord places the bet of the round id once,
+// so the repeated bet returns the transaction of the original one if it was placed.
+// The declined round is deleted, the placed round is saved to the history or rolled back without the record.
+// The placed round of the game without history is deleted.
+func (s *RoundService) reconcile(ctx context.Context, gameState *entities.GameState, round *rounds.Round) error {
+	bet, err := s.lord.AtomicBet(ctx, gameState.SessionToken.String(), round.FreeSpinID, round.ID.String(), round.Wager, round.Award, false)
+	if err != nil {
+		if !overlord.IsDeclined(err) {
+			return errs.TranslateOverlordErr(err)
+		}
+
+		zap.S().Infow("round bet is declined", "round_id", round.ID, "error", err)
+
+		s.Cancel(ctx, round)
+		s.idempotencySrv.Release(round.ID)
+
+		return nil
+	}
+
+	if round.NoHistory {
+		zap.S().Infow("round bet is reconciled", "round_id", round.ID, "transaction_id", bet.TransactionId)
+
+		return s.storage.Delete(ctx, round.ID)
+	}
+
+	spinIn := &history.SpinIn{}
+	if len(round.Record) == 0 || proto.Unmarshal(round.Record, spinIn) != nil {
+		return s.Rollback(ctx, round, bet.TransactionId)
+	}
+
+	// free spins are paid by the operator
+	charged := round.Wager
+	if round.FreeSpinID != "" {
+		charged = 0
+	}
+
+	spinIn.TransactionId = bet.TransactionId
+	spinIn.StartBalance = uint64(bet.Balance - round.Award + charged)
+	spinIn.EndBalance = uint64(bet.Balance)
+
+	data, err := proto.Marshal(spinIn)
+	if err != nil {
+		return err
+	}
+
+	s.mark(ctx, round, rounds.StatusPaid, bet.TransactionId, data)
+
+	zap.S().Infow("round bet is reconciled", "round_id", round.ID, "transaction_id", bet.TransactionId)
+
+	return s.saveRecord(ctx, round)
+}
+
+// marshalRecord returns the record of the round for the recovery, the games without history have no records.
+func (s *RoundService) marshalRecord(record *entities.HistoryRecord, metaData *entities.PlayerMetaData) ([]byte, error) {
+	if s.boot.HistoryHandlingType == engine.NoHistory {
+		return nil, nil
+	}
+
+	spinIn, err := record.ToHistoryServiceIn(metaData)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(spinIn)
+}
+
+func (s *RoundService) saveRecord(ctx context.Context, round *rounds.Round) error {
+	spinIn := &history.SpinIn{}
+	if err := proto.Unmarshal(round.Record, spinIn); err != nil {
+		return err
+	}
+
+	// the previous attempt could save the record without the response
+	_, err := s.historySrv.historyClient.GetByID(ctx, round.ID)
+	if errors.Is(err, history.ErrSpinNotFound) {
+		err = s.historySrv.create(ctx, spinIn)
+	}
+
+	if err != nil {
+		return errs.TranslateHistoryErr(err)
+	}
+
+	zap.S().Infow("round is saved to the history", "round_id", round.ID)
+
+	return s.storage.Delete(ctx, round.ID)
+}
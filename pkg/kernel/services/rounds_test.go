@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/history"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/overlord"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rounds"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var errTestUnavailable = errors.New("test unavailable")
+
+// testLord places the bet of the round id once like overlord.
+type testLord struct {
+	overlord.Client
+
+	mu          sync.Mutex
+	bets        map[string]*overlord.AtomicBetOut // round id -> placed bet
+	betErrs     map[string]error                  // round id -> error of the bet
+	rollbacks   []string
+	rollbackErr error
+}
+
+func newTestLord() *testLord {
+	return &testLord{bets: map[string]*overlord.AtomicBetOut{}, betErrs: map[string]error{}}
+}
+
+func (l *testLord) AtomicBet(_ context.Context, _, _, roundID string, wager, award int64, _ bool) (*overlord.AtomicBetOut, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.betErrs[roundID]; err != nil {
+		return nil, err
+	}
+
+	if bet, ok := l.bets[roundID]; ok {
+		return bet, nil
+	}
+
+	bet := &overlord.AtomicBetOut{TransactionId: uuid.NewString(), Balance: 1000 - wager + award}
+	l.bets[roundID] = bet
+
+	return bet, nil
+}
+
+func (l *testLord) RollbackBet(_ context.Context, _, transactionID string) (*overlord.RollbackOut, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rollbackErr != nil {
+		return nil, l.rollbackErr
+	}
+
+	l.rollbacks = append(l.rollbacks, transactionID)
+
+	return &overlord.RollbackOut{}, nil
+}
+
+type testHistory struct {
+	history.Client
+
+	mu        sync.Mutex
+	spins     map[string]*history.SpinIn
+	createErr error
+}
+
+func (h *testHistory) Create(_ context.Context, record *history.SpinIn) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.createErr != nil {
+		return h.createErr
+	}
+
+	h.spins[record.Id] = record
+
+	return nil
+}
+
+func (h *testHistory) GetByID(_ context.Context, id uuid.UUID) (*history.SpinOut, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.spins[id.String()]; !ok {
+		return nil, history.ErrSpinNotFound
+	}
+
+	return &history.SpinOut{}, nil
+}
+
+func newTestRoundService(handling engine.HistoryType) (*RoundService, *testLord, *testHistory) {
+	lord, hist := newTestLord(), &testHistory{spins: map[string]*history.SpinIn{}}
+
+	return &RoundService{
+		storage:        rounds.NewMemoryStorage(),
+		lord:           lord,
+		historySrv:     &HistoryService{historyClient: hist},
+		idempotencySrv: NewIdempotencyService(nil),
+		jackpotSrv:     newTestJackpotService(),
+		boot:           &engine.Bootstrap{HistoryHandlingType: handling},
+	}, lord, hist
+}
+
+func newTestRecord(userID uuid.UUID) *entities.HistoryRecord {
+	return &entities.HistoryRecord{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Game:         "test",
+		SessionToken: uuid.New(),
+		Wager:        100,
+		FinalAward:   300,
+		Spin:         &testSpin{Wagered: 100, Base: 300},
+		CreatedAt:    time.Now(),
+	}
+}
+
+// storedRound returns the saved round of the record, nil if it is deleted.
+func storedRound(t *testing.T, s *RoundService, record *entities.HistoryRecord) *rounds.Round {
+	unconfirmed, err := s.storage.Unconfirmed(context.Background(), record.UserID, record.Game)
+	require.NoError(t, err)
+
+	for _, round := range unconfirmed {
+		if round.ID == record.ID {
+			return round
+		}
+	}
+
+	return nil
+}
+
+func unmarshalRecord(t *testing.T, round *rounds.Round) *history.SpinIn {
+	spinIn := &history.SpinIn{}
+	require.NoError(t, proto.Unmarshal(round.Record, spinIn))
+
+	return spinIn
+}
+
+// ageRounds makes the rounds old enough for the recovery.
+func ageRounds(t *testing.T, s *RoundService, userID uuid.UUID) {
+	unconfirmed, err := s.storage.Unconfirmed(context.Background(), userID, "test")
+	require.NoError(t, err)
+
+	for _, round := range unconfirmed {
+		round.UpdatedAt = time.Now().Add(-2 * roundRecoveryDelay)
+		require.NoError(t, s.storage.Save(context.Background(), round))
+	}
+}
+
+func TestRoundService_OpenPaid(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestRoundService(engine.SequentialRestoring)
+	record := newTestRecord(uuid.New())
+
+	round, err := s.Open(ctx, record, "free-spin", 350, nil)
+	require.NoError(t, err)
+
+	// the record is saved before the bet
+	stored := storedRound(t, s, record)
+	require.Equal(t, rounds.StatusPending, stored.Status)
+	require.Equal(t, int64(100), stored.Wager)
+	require.Equal(t, int64(350), stored.Award)
+	require.Equal(t, "free-spin", stored.FreeSpinID)
+	require.Equal(t, record.ID.String(), unmarshalRecord(t, stored).Id)
+
+	record.TransactionID = uuid.New()
+	s.Paid(ctx, round, record)
+
+	stored = storedRound(t, s, record)
+	require.Equal(t, rounds.StatusPaid, stored.Status)
+	require.Equal(t, record.TransactionID.String(), stored.TransactionID)
+	require.Equal(t, record.TransactionID.String(), unmarshalRecord(t, stored).TransactionId)
+
+	// the games without history have no records
+	s, _, _ = newTestRoundService(engine.NoHistory)
+
+	_, err = s.Open(ctx, record, "", 300, nil)
+	require.NoError(t, err)
+	require.Empty(t, storedRound(t, s, record).Record)
+}
+
+func TestRoundService_Rollback(t *testing.T) {
+	ctx := context.Background()
+	s, lord, _ := newTestRoundService(engine.SequentialRestoring)
+	record := newTestRecord(uuid.New())
+
+	round, err := s.Open(ctx, record, "", 300, nil)
+	require.NoError(t, err)
+
+	// the failed rollback keeps the round without the record, it is rolled back on the recovery
+	lord.rollbackErr = errTestUnavailable
+
+	require.Error(t, s.Rollback(ctx, round, "transaction"))
+
+	stored := storedRound(t, s, record)
+	require.Equal(t, rounds.StatusRollback, stored.Status)
+	require.Equal(t, "transaction", stored.TransactionID)
+	require.Empty(t, stored.Record)
+
+	// the rolled back round releases the idempotency key
+	_, _, err = s.idempotencySrv.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+	s.idempotencySrv.Block("session", "key", record.ID)
+
+	lord.rollbackErr = nil
+
+	require.NoError(t, s.Rollback(ctx, round, "transaction"))
+	require.Nil(t, storedRound(t, s, record))
+	require.Equal(t, []string{"transaction"}, lord.rollbacks)
+
+	_, _, err = s.idempotencySrv.Begin("session", "key", idempotentRequest{Wager: 100})
+	require.NoError(t, err)
+}
+
+func TestRoundService_Confirm(t *testing.T) {
+	ctx := context.Background()
+	s, _, hist := newTestRoundService(engine.SequentialRestoring)
+	metaData := &entities.PlayerMetaData{IP: "127.0.0.1"}
+
+	record := newTestRecord(uuid.New())
+	record.TransactionID = uuid.New()
+
+	_, err := s.Open(ctx, record, "", 300, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Confirm(ctx, record, metaData))
+	require.Nil(t, storedRound(t, s, record))
+	require.Equal(t, "127.0.0.1", hist.spins[record.ID.String()].ClientIp)
+
+	// the record is kept with the metadata if the history fails
+	hist.createErr = errTestUnavailable
+	record = newTestRecord(record.UserID)
+
+	require.Error(t, s.Confirm(ctx, record, metaData))
+
+	stored := storedRound(t, s, record)
+	require.Equal(t, rounds.StatusPaid, stored.Status)
+	require.Equal(t, "127.0.0.1", unmarshalRecord(t, stored).ClientIp)
+}
+
+func TestRoundService_Recover(t *testing.T) {
+	ctx := context.Background()
+	s, lord, hist := newTestRoundService(engine.SequentialRestoring)
+	userID := uuid.New()
+
+	open := func(record *entities.HistoryRecord) *rounds.Round {
+		round, err := s.Open(ctx, record, "", 300, nil)
+		require.NoError(t, err)
+
+		return round
+	}
+
+	// the bet is placed, the response is lost
+	placed := newTestRecord(userID)
+	open(placed)
+	bet, err := lord.AtomicBet(ctx, "", "", placed.ID.String(), 100, 300, false)
+	require.NoError(t, err)
+
+	// the bet is never placed
+	declined := newTestRecord(userID)
+	open(declined)
+	lord.betErrs[declined.ID.String()] = overlord.ErrBalanceTooLow
+
+	// overlord is still unavailable
+	unknown := newTestRecord(userID)
+	open(unknown)
+	lord.betErrs[unknown.ID.String()] = errTestUnavailable
+
+	// the paid round without the record
+	rolledBack := newTestRecord(userID)
+	require.NoError(t, s.storage.Save(ctx, &rounds.Round{
+		ID: rolledBack.ID, UserID: userID, Game: "test", Status: rounds.StatusPaid, TransactionID: "rolled-back",
+	}))
+
+	// the paid round with the record
+	paid := newTestRecord(userID)
+	paid.TransactionID = uuid.New()
+	s.Paid(ctx, open(paid), paid)
+
+	ageRounds(t, s, userID)
+
+	// the round which is just opened can be still in the wager
+	recent := newTestRecord(userID)
+	open(recent)
+
+	require.NoError(t, s.Recover(ctx, &entities.GameState{UserID: userID, Game: "test", SessionToken: uuid.New()}))
+
+	reconciled := hist.spins[placed.ID.String()]
+	require.NotNil(t, reconciled)
+	require.Equal(t, bet.TransactionId, reconciled.TransactionId)
+	require.Equal(t, uint64(1200), reconciled.EndBalance)
+	require.Equal(t, uint64(1000), reconciled.StartBalance)
+	require.Nil(t, storedRound(t, s, placed))
+
+	require.Nil(t, storedRound(t, s, declined))
+	require.NotContains(t, hist.spins, declined.ID.String())
+
+	require.Equal(t, rounds.StatusPending, storedRound(t, s, unknown).Status)
+
+	require.Nil(t, storedRound(t, s, rolledBack))
+	require.Equal(t, []string{"rolled-back"}, lord.rollbacks)
+
+	require.Equal(t, paid.TransactionID.String(), hist.spins[paid.ID.String()].TransactionId)
+	require.Nil(t, storedRound(t, s, paid))
+
+	require.Equal(t, rounds.StatusPending, storedRound(t, s, recent).Status)
+}
+
+func TestRoundService_Recover_NoHistory(t *testing.T) {
+	ctx := context.Background()
+	s, lord, _ := newTestRoundService(engine.NoHistory)
+	userID := uuid.New()
+
+	open := func() (*entities.HistoryRecord, *rounds.Round) {
+		record := newTestRecord(userID)
+
+		round, err := s.Open(ctx, record, "", 300, nil)
+		require.NoError(t, err)
+		require.True(t, round.NoHistory)
+
+		return record, round
+	}
+
+	// the round is paid, the server fails before it is deleted
+	paid, round := open()
+	paid.TransactionID = uuid.New()
+	s.Paid(ctx, round, paid)
+
+	// the bet is placed, the response is lost
+	placed, _ := open()
+
+	// the rollback of the round fails
+	rolledBack, round := open()
+	lord.rollbackErr = errTestUnavailable
+	require.Error(t, s.Rollback(ctx, round, "rolled-back"))
+	lord.rollbackErr = nil
+
+	ageRounds(t, s, userID)
+
+	require.NoError(t, s.Recover(ctx, &entities.GameState{UserID: userID, Game: "test", SessionToken: uuid.New()}))
+
+	// the paid rounds are deleted without the rollback
+	require.Nil(t, storedRound(t, s, paid))
+	require.Nil(t, storedRound(t, s, placed))
+	require.Contains(t, lord.bets, placed.ID.String())
+
+	require.Nil(t, storedRound(t, s, rolledBack))
+	require.Equal(t, []string{"rolled-back"}, lord.rollbacks)
+}
+
+func TestRoundService_Recover_DeclinedJackpot(t *testing.T) {
+	ctx := context.Background()
+	s, lord, _ := newTestRoundService(engine.SequentialRestoring)
+	jackpots := s.jackpotSrv
+	userID := uuid.New()
+
+	_, err := jackpots.storage.Contribute(ctx, jackpots.key("game", "usd", "grand"), 1000, 95)
+	require.NoError(t, err)
+
+	// settle opens the round which won the jackpot, the pool is reset to the seed
+	settle := func() (*entities.HistoryRecord, *JackpotSettlement) {
+		requests, err := jackpots.Requests(ctx, "game", "usd", 1, 500)
+		require.NoError(t, err)
+
+		requests.Requested = []string{"grand"}
+
+		settlement, err := jackpots.Settle(ctx, "game", "usd", 1, requests, &testSpin{Wagered: 500})
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), grandValue(t, jackpots))
+
+		record := newTestRecord(userID)
+
+		_, err = s.Open(ctx, record, "", 300+settlement.Award(), settlement)
+		require.NoError(t, err)
+
+		return record, settlement
+	}
+
+	// the bet of the round is declined by the recovery
+	declined, _ := settle()
+	lord.betErrs[declined.ID.String()] = overlord.ErrBalanceTooLow
+
+	ageRounds(t, s, userID)
+
+	require.NoError(t, s.Recover(ctx, &entities.GameState{UserID: userID, Game: "test", SessionToken: uuid.New()}))
+	require.Nil(t, storedRound(t, s, declined))
+
+	// the won jackpot and the contribution are returned to the pool
+	require.Equal(t, int64(1095), grandValue(t, jackpots))
+
+	// the placed bet is rolled back
+	rolledBack, _ := settle()
+
+	round := storedRound(t, s, rolledBack)
+	require.NotEmpty(t, round.Jackpots)
+	require.NoError(t, s.Rollback(ctx, round, "transaction"))
+	require.Equal(t, int64(1095), grandValue(t, jackpots))
+
+	// the reconciled round keeps the jackpots
+	placed, settlement := settle()
+
+	ageRounds(t, s, userID)
+
+	require.NoError(t, s.Recover(ctx, &entities.GameState{UserID: userID, Game: "test", SessionToken: uuid.New()}))
+	require.Nil(t, storedRound(t, s, placed))
+	require.Equal(t, int64(1100), settlement.Award())
+	require.Equal(t, int64(1000), grandValue(t, jackpots))
+}
+
+func TestRoundService_Recover_ContinuesAfterError(t *testing.T) {
+	ctx := context.Background()
+	s, lord, hist := newTestRoundService(engine.SequentialRestoring)
+	userID := uuid.New()
+
+	first, second := newTestRecord(userID), newTestRecord(userID)
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+
+	for _, record := range []*entities.HistoryRecord{first, second} {
+		_, err := s.Open(ctx, record, "", 300, nil)
+		require.NoError(t, err)
+	}
+
+	// the oldest round fails, the next one is recovered
+	lord.betErrs[first.ID.String()] = errTestUnavailable
+
+	ageRounds(t, s, userID)
+
+	require.NoError(t, s.Recover(ctx, &entities.GameState{UserID: userID, Game: "test", SessionToken: uuid.New()}))
+
+	require.NotNil(t, storedRound(t, s, first))
+	require.Nil(t, storedRound(t, s, second))
+	require.Contains(t, hist.spins, second.ID.String())
+}
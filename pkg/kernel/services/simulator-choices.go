@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"github.com/samber/lo"
+)
+
+const (
+	RandomChoicePolicy = "random"
+	FixedChoicePolicy  = "fixed"
+	AllChoicesPolicy   = "all"
+
+	// maxChoiceSteps stops the engines that never finish the choices
+	maxChoiceSteps = 100
+)
+
+var ErrTooManyChoices = errors.New("spin generation is not finished after max choice steps")
+
+// ChoicePolicy picks the choice of engine.ChoiceSpin in the simulation.
+type ChoicePolicy interface {
+	Choose(rand rng.Client, path ChoicePath, choices []any) (int, error)
+}
+
+// ChoicePath is the state of the choices of the simulated spin before the current one.
+type ChoicePath struct {
+	Index   int64 // the number of the simulated spin
+	Offered []int // the numbers of choices offered at the previous steps
+	Chosen  []int // the choices made at the previous steps
+}
+
+// NewChoicePolicy returns the policy by the name, empty name means random, fixed is the choice index of the fixed policy.
+func NewChoicePolicy(name string, fixed int) (ChoicePolicy, error) {
+	switch name {
+	case RandomChoicePolicy, "":
+		return randomChoice{}, nil
+	case FixedChoicePolicy:
+		if fixed < 0 {
+			return nil, fmt.Errorf("choice index must not be negative, got %d", fixed)
+		}
+
+		return fixedChoice{index: fixed}, nil
+	case AllChoicesPolicy:
+		return allChoices{}, nil
+	default:
+		return nil, fmt.Errorf("unknown choice policy %s", name)
+	}
+}
+
+type randomChoice struct{}
+
+func (randomChoice) Choose(rand rng.Client, _ ChoicePath, choices []any) (int, error) {
+	index, err := rand.Rand(uint64(len(choices)))
+
+	return int(index), err
+}
+
+// fixedChoice picks the last choice if the spin has less choices than the index.
+type fixedChoice struct {
+	index int
+}
+
+func (f fixedChoice) Choose(_ rng.Client, _ ChoicePath, choices []any) (int, error) {
+	return min(f.index, len(choices)-1), nil
+}
+
+// allChoices enumerates the combinations of the choices in turn: the spin index is a mixed radix number
+// with a digit per step, so every combination is played by the same share of spins.
+// For two steps of two choices the spins play (0, 0), (1, 0), (0, 1), (1, 1) and so on.
+type allChoices struct{}
+
+func (allChoices) Choose(_ rng.Client, path ChoicePath, choices []any) (int, error) {
+	combinations := int64(1)
+	for _, offered := range path.Offered {
+		combinations *= int64(offered)
+	}
+
+	return int(path.Index / combinations % int64(len(choices))), nil
+}
+
+func (s *SimulatorService) WithChoicePolicy(policy ChoicePolicy) *SimulatorService {
+	s.choicePolicy = policy
+
+	return s
+}
+
+// choicePolicyFor returns the policy of the config or the one set with WithChoicePolicy.
+func (s *SimulatorService) choicePolicyFor(cfg *SimulatorConfig) (ChoicePolicy, error) {
+	if cfg.ChoicePolicy == "" {
+		return s.choicePolicy, nil
+	}
+
+	return NewChoicePolicy(cfg.ChoicePolicy, cfg.ChoiceIndex)
+}
+
+// applyChoices makes the choices of the spin with the policy until the spin offers no choices.
+// It returns the spin and the made choices joined by " > ", empty if the spin had no choices.
+// The offered choice which the factory can not continue fails the simulation as it fails the round in production.
+func (s *SimulatorService) applyChoices(ctx engine.Context, spin engine.Spin, factory engine.SpinFactory, index int64) (
+	engine.Spin, string, error,
+) {
+	policy := s.choicePolicy
+	if policy == nil {
+		policy = randomChoice{}
+	}
+
+	var (
+		path   []string
+		choice = ChoicePath{Index: index}
+	)
+
+	for step := 0; ; step++ {
+		choiceSpin, ok := spin.(engine.ChoiceSpin)
+		if !ok || len(choiceSpin.Choices()) == 0 {
+			break
+		}
+
+		if step == maxChoiceSteps {
+			return nil, "", ErrTooManyChoices
+		}
+
+		choices := choiceSpin.Choices()
+
+		chosen, err := policy.Choose(factory.GetRngClient(), choice, choices)
+		if err != nil {
+			return nil, "", err
+		}
+
+		choice.Offered = append(choice.Offered, len(choices))
+		choice.Chosen = append(choice.Chosen, chosen)
+
+		ctx.LastSpin = spin
+
+		next, ok, err := factory.KeepGenerate(ctx, choices[chosen])
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !ok {
+			return nil, "", fmt.Errorf("choice %v of spin %d: %w", choices[chosen], index, errs.ErrSpinGenerationCanNotBeContinued)
+		}
+
+		path = append(path, fmt.Sprint(choices[chosen]))
+		spin = next
+	}
+
+	return spin, strings.Join(path, " > "), nil
+}
+
+type ChoiceView struct {
+	Choice string `json:"choice" xlsx:"Choice"`
+	Count  string `json:"count" xlsx:"Count"`
+	Rate   string `json:"rate" xlsx:"Rate"`
+	Spent  string `json:"spent" xlsx:"Spent"`
+	Award  string `json:"award" xlsx:"Award"`
+	RTP    string `json:"rtp" xlsx:"RTP"`
+}
+
+func (r SimulationResult) addChoice(choice string, wager, award int64) {
+	if choice == "" {
+		return
+	}
+
+	result, ok := r.Choices[choice]
+	if !ok {
+		result = &FeatureResult{Feature: choice, Spent: new(big.Int), Award: new(big.Int)}
+		r.Choices[choice] = result
+	}
+
+	result.Count++
+	result.Spent.Add(result.Spent, big.NewInt(wager))
+	result.Award.Add(result.Award, big.NewInt(award))
+}
+
+func (r SimulationResult) choiceViews() []*ChoiceView {
+	choices := lo.Keys(r.Choices)
+	sort.Strings(choices)
+
+	return lo.Map(choices, func(item string, _ int) *ChoiceView {
+		view := r.Choices[item].View(r.Count)
+
+		return &ChoiceView{
+			Choice: view.Feature,
+			Count:  view.Count,
+			Rate:   view.Rate,
+			Spent:  view.Spent,
+			Award:  view.Award,
+			RTP:    view.RTP,
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+// choiceSpin offers two choices until left steps are made.
+type choiceSpin struct {
+	testSpin
+	Left int
+	Made []string
+}
+
+func (s *choiceSpin) Choices() []any {
+	if s.Left == 0 {
+		return nil
+	}
+
+	return []any{"a", "b"}
+}
+
+func (s *choiceSpin) DeepCopy() engine.Spin {
+	cp := *s
+	cp.Made = append([]string(nil), s.Made...)
+
+	return &cp
+}
+
+// choiceFactory generates spins with steps choices, KeepGenerate stops after stopAfter choices if it is set.
+type choiceFactory struct {
+	*testFactory
+	steps     int
+	stopAfter int
+}
+
+func (f *choiceFactory) Generate(_ engine.Context, wager int64, _ interface{}) (engine.Spin, engine.RestoringIndexes, error) {
+	return &choiceSpin{testSpin: testSpin{Wagered: wager}, Left: f.steps}, nil, nil
+}
+
+func (f *choiceFactory) KeepGenerate(ctx engine.Context, parameters interface{}) (engine.Spin, bool, error) {
+	spin := ctx.LastSpin.DeepCopy().(*choiceSpin)
+	if f.stopAfter > 0 && len(spin.Made) == f.stopAfter {
+		return spin, false, nil
+	}
+
+	spin.Left--
+	spin.Made = append(spin.Made, fmt.Sprint(parameters))
+
+	return spin, true, nil
+}
+
+func TestNewChoicePolicy(t *testing.T) {
+	for name, want := range map[string]ChoicePolicy{
+		"":                 randomChoice{},
+		RandomChoicePolicy: randomChoice{},
+		FixedChoicePolicy:  fixedChoice{index: 1},
+		AllChoicesPolicy:   allChoices{},
+	} {
+		policy, err := NewChoicePolicy(name, 1)
+		require.NoError(t, err)
+		require.Equal(t, want, policy)
+	}
+
+	_, err := NewChoicePolicy(FixedChoicePolicy, -1)
+	require.Error(t, err)
+
+	_, err = NewChoicePolicy("first", 0)
+	require.Error(t, err)
+}
+
+func TestChoicePolicies(t *testing.T) {
+	choices := []any{"a", "b", "c"}
+
+	chosen, err := fixedChoice{index: 1}.Choose(nil, ChoicePath{}, choices)
+	require.NoError(t, err)
+	require.Equal(t, 1, chosen)
+
+	// the spin with less choices gets the last one
+	chosen, err = fixedChoice{index: 5}.Choose(nil, ChoicePath{}, choices)
+	require.NoError(t, err)
+	require.Equal(t, 2, chosen)
+
+	rand := rng.NewSeededClient(1)
+
+	for i := 0; i < 100; i++ {
+		chosen, err = randomChoice{}.Choose(rand, ChoicePath{}, choices)
+		require.NoError(t, err)
+		require.Less(t, chosen, len(choices))
+	}
+}
+
+func TestAllChoices_Combinations(t *testing.T) {
+	tests := []struct {
+		name    string
+		offered []int
+		want    []int // choices of the spins 0..len(want)-1
+	}{
+		{name: "first step", offered: nil, want: []int{0, 1, 2, 0, 1, 2}},
+		{name: "second step after two choices", offered: []int{2}, want: []int{0, 0, 1, 1, 2, 2, 0}},
+		{name: "third step after 2x3 choices", offered: []int{2, 3}, want: []int{0, 0, 0, 0, 0, 0, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for index, want := range tt.want {
+				got, err := allChoices{}.Choose(nil, ChoicePath{Index: int64(index), Offered: tt.offered}, []any{"a", "b", "c"})
+				require.NoError(t, err)
+				require.Equal(t, want, got, index)
+			}
+		})
+	}
+}
+
+func TestSimulatorService_ApplyChoices(t *testing.T) {
+	factory := &choiceFactory{testFactory: newTestFactory(1, 1), steps: 2}
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: factory}).WithChoicePolicy(allChoices{})
+
+	ctx := engine.Context{Context: context.Background()}
+
+	var paths []string
+
+	for index := int64(0); index < 4; index++ {
+		spin, _, err := factory.Generate(ctx, 100, nil)
+		require.NoError(t, err)
+
+		spin, path, err := s.applyChoices(ctx, spin, factory, index)
+		require.NoError(t, err)
+		require.Empty(t, spin.(*choiceSpin).Choices())
+
+		paths = append(paths, path)
+	}
+
+	// all the combinations of two steps are played
+	require.Equal(t, []string{"a > a", "b > a", "a > b", "b > b"}, paths)
+}
+
+func TestSimulatorService_ApplyChoices_Stops(t *testing.T) {
+	ctx := engine.Context{Context: context.Background()}
+
+	// the spin without choices is kept
+	s := newTestSimulator(nil)
+	spin, path, err := s.applyChoices(ctx, &testSpin{Base: 10}, newTestFactory(1, 1), 0)
+	require.NoError(t, err)
+	require.Equal(t, &testSpin{Base: 10}, spin)
+	require.Empty(t, path)
+
+	// the factory can not continue the offered choice, the round is impossible in production
+	s.WithChoicePolicy(fixedChoice{})
+
+	factory := &choiceFactory{testFactory: newTestFactory(1, 1), steps: 3, stopAfter: 1}
+	spin, _, _ = factory.Generate(ctx, 100, nil)
+
+	_, _, err = s.applyChoices(ctx, spin, factory, 7)
+	require.ErrorIs(t, err, errs.ErrSpinGenerationCanNotBeContinued)
+	require.ErrorContains(t, err, "choice a of spin 7")
+
+	// the engine never finishes the choices
+	factory = &choiceFactory{testFactory: newTestFactory(1, 1), steps: maxChoiceSteps + 1}
+	spin, _, _ = factory.Generate(ctx, 100, nil)
+
+	_, _, err = s.applyChoices(ctx, spin, factory, 0)
+	require.ErrorIs(t, err, ErrTooManyChoices)
+}
+
+func TestSimulatorService_Simulate_AllChoices(t *testing.T) {
+	factory := &choiceFactory{testFactory: newTestFactory(1, 1), steps: 2}
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: factory}).WithChoicePolicy(allChoices{})
+
+	res, err := s.Simulate(context.Background(), "test", 4000, 100, 4)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"a > a", "a > b", "b > a", "b > b"}, lo.Keys(res.Choices))
+
+	for _, choice := range res.Choices {
+		require.Equal(t, int64(1000), choice.Count)
+	}
+}
+
+func TestSimulatorService_Simulate_ChoiceCanNotBeContinued(t *testing.T) {
+	factory := &choiceFactory{testFactory: newTestFactory(1, 1), steps: 2, stopAfter: 1}
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: factory}).WithChoicePolicy(allChoices{})
+
+	_, err := s.Simulate(context.Background(), "test", 100, 100, 2)
+	require.ErrorIs(t, err, errs.ErrSpinGenerationCanNotBeContinued)
+}
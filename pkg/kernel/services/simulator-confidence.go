@@ -0,0 +1,104 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/samber/lo"
+)
+
+type confidenceLevel struct {
+	Level float64
+	Z     float64 // z-score of the level
+}
+
+// confidenceLevels are the levels of the RTP confidence intervals.
+var confidenceLevels = []confidenceLevel{
+	{Level: 0.90, Z: 1.6449},
+	{Level: 0.95, Z: 1.9600},
+	{Level: 0.99, Z: 2.5758},
+}
+
+const (
+	precisionZ         = 1.9600 // spins for the target precision are computed for 95% confidence
+	defaultCheckpoints = 100
+)
+
+type ConfidenceInterval struct {
+	Level float64
+	Low   float64
+	High  float64
+}
+
+type Checkpoint struct {
+	Spins int64
+	RTP   float64
+}
+
+// rtpStandardError is the standard error of RTP: standard deviation of award per spent unit divided by sqrt(n).
+func rtpStandardError(res *SimulationResult) float64 {
+	if res.Count == 0 || res.Spent.Sign() == 0 {
+		return 0
+	}
+
+	sd, _ := res.AwardStandardDeviation.Float64()
+	spent, _ := new(big.Float).SetInt(res.Spent).Float64()
+
+	return sd / (spent / float64(res.Count)) / math.Sqrt(float64(res.Count))
+}
+
+func confidenceIntervals(res *SimulationResult) []ConfidenceInterval {
+	se := rtpStandardError(res)
+
+	return lo.Map(confidenceLevels, func(item confidenceLevel, _ int) ConfidenceInterval {
+		return ConfidenceInterval{Level: item.Level, Low: res.RTP - item.Z*se, High: res.RTP + item.Z*se}
+	})
+}
+
+// spinsForPrecision returns the number of spins needed to get RTP within ±precision with 95% confidence.
+func spinsForPrecision(res *SimulationResult, precision float64) int64 {
+	if precision <= 0 {
+		return 0
+	}
+
+	// standard deviation of a single spin RTP
+	sd := rtpStandardError(res) * math.Sqrt(float64(res.Count))
+
+	return int64(math.Ceil(math.Pow(precisionZ*sd/precision, 2)))
+}
+
+func checkpointStep(count, every int64) int64 {
+	if every > 0 {
+		return every
+	}
+
+	return max(count/defaultCheckpoints, 1)
+}
+
+type ConfidenceIntervalView struct {
+	Level string `json:"level" xlsx:"Level"`
+	Low   string `json:"low" xlsx:"RTP Low"`
+	High  string `json:"high" xlsx:"RTP High"`
+}
+
+type CheckpointView struct {
+	Spins string `json:"spins" xlsx:"Spins"`
+	RTP   string `json:"rtp" xlsx:"RTP"`
+}
+
+func (r SimulationResult) confidenceViews() []*ConfidenceIntervalView {
+	return lo.Map(r.ConfidenceIntervals, func(item ConfidenceInterval, _ int) *ConfidenceIntervalView {
+		return &ConfidenceIntervalView{
+			Level: fmt.Sprintf("%.0f%%", item.Level*100),
+			Low:   floatWithPrecision(item.Low),
+			High:  floatWithPrecision(item.High),
+		}
+	})
+}
+
+func (r SimulationResult) convergenceViews() []*CheckpointView {
+	return lo.Map(r.Convergence, func(item Checkpoint, _ int) *CheckpointView {
+		return &CheckpointView{Spins: fmt.Sprint(item.Spins), RTP: floatWithPrecision(item.RTP)}
+	})
+}
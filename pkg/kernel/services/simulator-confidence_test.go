@@ -0,0 +1,116 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// confidenceResult returns the result of count spins with the award standard deviation sd and the given RTP.
+func confidenceResult(count, wager int64, sd, rtp float64) *SimulationResult {
+	res := newTestResult(wager)
+	res.Count = count
+	res.Spent = big.NewInt(count * wager)
+	res.AwardStandardDeviation = big.NewFloat(sd)
+	res.RTP = rtp
+
+	return res
+}
+
+func TestConfidenceIntervals(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *SimulationResult
+		want []ConfidenceInterval
+	}{
+		{
+			name: "zero spins",
+			res:  newTestResult(100),
+			want: []ConfidenceInterval{{Level: 0.90}, {Level: 0.95}, {Level: 0.99}},
+		},
+		{
+			name: "no deviation",
+			res:  confidenceResult(100, 100, 0, 0.95),
+			want: []ConfidenceInterval{
+				{Level: 0.90, Low: 0.95, High: 0.95},
+				{Level: 0.95, Low: 0.95, High: 0.95},
+				{Level: 0.99, Low: 0.95, High: 0.95},
+			},
+		},
+		{
+			// standard error is 200 / 100 / sqrt(100) = 0.2
+			name: "known deviation",
+			res:  confidenceResult(100, 100, 200, 0.95),
+			want: []ConfidenceInterval{
+				{Level: 0.90, Low: 0.95 - 1.6449*0.2, High: 0.95 + 1.6449*0.2},
+				{Level: 0.95, Low: 0.95 - 1.9600*0.2, High: 0.95 + 1.9600*0.2},
+				{Level: 0.99, Low: 0.95 - 2.5758*0.2, High: 0.95 + 2.5758*0.2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := confidenceIntervals(tt.res)
+			require.Len(t, got, len(tt.want))
+
+			for i := range tt.want {
+				require.Equal(t, tt.want[i].Level, got[i].Level)
+				require.InDelta(t, tt.want[i].Low, got[i].Low, 1e-9)
+				require.InDelta(t, tt.want[i].High, got[i].High, 1e-9)
+			}
+		})
+	}
+}
+
+func TestSpinsForPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		res       *SimulationResult
+		precision float64
+		want      int64
+	}{
+		{name: "zero spins", res: newTestResult(100), precision: 0.5, want: 0},
+		{name: "no precision", res: confidenceResult(100, 100, 200, 0.95), precision: 0, want: 0},
+		{name: "no deviation", res: confidenceResult(100, 100, 0, 0.95), precision: 0.5, want: 0},
+		// spin standard deviation is 200 / 100 = 2, (1.96 * 2 / 0.5)^2 = 61.4656
+		{name: "known deviation", res: confidenceResult(100, 100, 200, 0.95), precision: 0.5, want: 62},
+		{name: "independent of spins", res: confidenceResult(10000, 100, 200, 0.95), precision: 0.5, want: 62},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, spinsForPrecision(tt.res, tt.precision))
+		})
+	}
+}
+
+func TestCheckpointStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int64
+		every int64
+		want  int64
+	}{
+		{name: "zero spins", count: 0, every: 0, want: 1},
+		{name: "less than checkpoints", count: 50, every: 0, want: 1},
+		{name: "default checkpoints", count: 1_000_000, every: 0, want: 10_000},
+		{name: "configured", count: 1_000_000, every: 500, want: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, checkpointStep(tt.count, tt.every))
+		})
+	}
+}
+
+func TestSimulationResult_ConfidenceViews(t *testing.T) {
+	res := confidenceResult(100, 100, 200, 0.95)
+	res.ConfidenceIntervals = confidenceIntervals(res)
+	res.Convergence = []Checkpoint{{Spins: 50, RTP: 0.9}, {Spins: 100, RTP: 0.95}}
+
+	require.Equal(t, &ConfidenceIntervalView{Level: "95%", Low: "55.800", High: "134.200"}, res.confidenceViews()[1])
+	require.Equal(t, []*CheckpointView{{Spins: "50", RTP: "90.000"}, {Spins: "100", RTP: "95.000"}}, res.convergenceViews())
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	CSVSpinDump      = "csv"
+	ColumnarSpinDump = "columnar" // raw column files with schema.json, it is not Parquet
+)
+
+// SpinRecord is the row of the per-spin dump, the awards are after the max win cap.
+type SpinRecord struct {
+	Index          int64
+	OriginalWager  int64
+	Wager          int64
+	BaseAward      int64
+	BonusAward     int64
+	BonusTriggered bool
+	MaxWinReached  bool
+	Feature        string
+}
+
+// SpinDumpWriter gets the records in the order of spins.
+type SpinDumpWriter interface {
+	Write(record SpinRecord) error
+	Close() error
+}
+
+// spinDump is the target of the per-spin dump, base is the path without the extension.
+type spinDump struct {
+	Format string
+	Base   string
+}
+
+func (d *spinDump) open() (SpinDumpWriter, error) {
+	switch d.Format {
+	case CSVSpinDump:
+		return newCSVSpinDump(d.Base + ".csv")
+	case ColumnarSpinDump:
+		return newColumnarSpinDump(d.Base)
+	default:
+		return nil, fmt.Errorf("unknown spin dump format %s", d.Format)
+	}
+}
+
+var spinDumpColumns = []string{
+	"index", "original_wager", "wager", "base_award", "bonus_award", "bonus_triggered", "max_win_reached", "feature",
+}
+
+type csvSpinDump struct {
+	file *os.File
+	w    *csv.Writer
+}
+
+func newCSVSpinDump(path string) (SpinDumpWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &csvSpinDump{file: file, w: csv.NewWriter(file)}
+
+	if err := d.w.Write(spinDumpColumns); err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (d *csvSpinDump) Write(r SpinRecord) error {
+	return d.w.Write([]string{
+		strconv.FormatInt(r.Index, 10),
+		strconv.FormatInt(r.OriginalWager, 10),
+		strconv.FormatInt(r.Wager, 10),
+		strconv.FormatInt(r.BaseAward, 10),
+		strconv.FormatInt(r.BonusAward, 10),
+		strconv.FormatBool(r.BonusTriggered),
+		strconv.FormatBool(r.MaxWinReached),
+		r.Feature,
+	})
+}
+
+func (d *csvSpinDump) Close() error {
+	d.w.Flush()
+
+	if err := d.w.Error(); err != nil {
+		d.file.Close()
+
+		return err
+	}
+
+	return d.file.Close()
+}
+
+// columnarSpinDump writes every column to its own little-endian file in the base directory, schema.json
+// describes the columns, so the dump can be loaded without parsing (numpy.fromfile, polars, duckdb).
+// Bools are uint8, features are int32 codes of the schema dictionary.
+//
+// The dump is not Parquet: the server has no Parquet dependency, so the pipelines that need Parquet
+// convert the loaded columns themselves, for example with polars DataFrame.write_parquet.
+type columnarSpinDump struct {
+	dir     string
+	files   []*os.File
+	columns []*bufio.Writer
+
+	rows       int64
+	dictionary map[string]int32
+	features   []string
+}
+
+type columnarSchema struct {
+	Rows       int64            `json:"rows"`
+	Columns    []columnarColumn `json:"columns"`
+	Dictionary []string         `json:"dictionary"` // feature codes
+}
+
+type columnarColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	File string `json:"file"`
+}
+
+func newColumnarSpinDump(dir string) (SpinDumpWriter, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	d := &columnarSpinDump{dir: dir, dictionary: map[string]int32{}}
+
+	for _, column := range spinDumpColumns {
+		file, err := os.Create(filepath.Join(dir, column+".bin"))
+		if err != nil {
+			d.closeFiles()
+
+			return nil, err
+		}
+
+		d.files = append(d.files, file)
+		d.columns = append(d.columns, bufio.NewWriter(file))
+	}
+
+	return d, nil
+}
+
+func (d *columnarSpinDump) Write(r SpinRecord) error {
+	code, ok := d.dictionary[r.Feature]
+	if !ok {
+		code = int32(len(d.features))
+		d.dictionary[r.Feature] = code
+		d.features = append(d.features, r.Feature)
+	}
+
+	values := []any{
+		r.Index, r.OriginalWager, r.Wager, r.BaseAward, r.BonusAward,
+		boolToUint8(r.BonusTriggered), boolToUint8(r.MaxWinReached), code,
+	}
+
+	for i, value := range values {
+		if err := binary.Write(d.columns[i], binary.LittleEndian, value); err != nil {
+			return err
+		}
+	}
+
+	d.rows++
+
+	return nil
+}
+
+func (d *columnarSpinDump) Close() error {
+	defer d.closeFiles()
+
+	for _, column := range d.columns {
+		if err := column.Flush(); err != nil {
+			return err
+		}
+	}
+
+	types := []string{"int64", "int64", "int64", "int64", "int64", "uint8", "uint8", "int32"}
+
+	schema := columnarSchema{Rows: d.rows, Dictionary: d.features}
+
+	for i, column := range spinDumpColumns {
+		schema.Columns = append(schema.Columns, columnarColumn{Name: column, Type: types[i], File: column + ".bin"})
+	}
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(d.dir, "schema.json"), b, 0o644)
+}
+
+func (d *columnarSpinDump) closeFiles() {
+	for _, file := range d.files {
+		file.Close()
+	}
+}
+
+func boolToUint8(b bool) uint8 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSpinRecords = []SpinRecord{
+	{Index: 0, OriginalWager: 100, Wager: 100, BaseAward: 50, Feature: "base"},
+	{Index: 1, OriginalWager: 100, Wager: 10000, BonusAward: 500000, BonusTriggered: true, MaxWinReached: true, Feature: "buy:fs"},
+	{Index: 2, OriginalWager: 100, Wager: 100, Feature: "base"},
+}
+
+func writeSpinRecords(t *testing.T, d *spinDump) {
+	w, err := d.open()
+	require.NoError(t, err)
+
+	for _, record := range testSpinRecords {
+		require.NoError(t, w.Write(record))
+	}
+
+	require.NoError(t, w.Close())
+}
+
+func TestCSVSpinDump(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "spins")
+
+	writeSpinRecords(t, &spinDump{Format: CSVSpinDump, Base: base})
+
+	file, err := os.Open(base + ".csv")
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, len(testSpinRecords)+1)
+	require.Equal(t, spinDumpColumns, rows[0])
+	require.Equal(t, []string{"1", "100", "10000", "0", "500000", "true", "true", "buy:fs"}, rows[2])
+}
+
+func TestColumnarSpinDump(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spins")
+
+	writeSpinRecords(t, &spinDump{Format: ColumnarSpinDump, Base: dir})
+
+	data, err := os.ReadFile(filepath.Join(dir, "schema.json"))
+	require.NoError(t, err)
+
+	var schema columnarSchema
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	require.Equal(t, int64(len(testSpinRecords)), schema.Rows)
+	require.Equal(t, []string{"base", "buy:fs"}, schema.Dictionary)
+	require.Len(t, schema.Columns, len(spinDumpColumns))
+	require.Equal(t, columnarColumn{Name: "bonus_award", Type: "int64", File: "bonus_award.bin"}, schema.Columns[4])
+	require.Equal(t, columnarColumn{Name: "bonus_triggered", Type: "uint8", File: "bonus_triggered.bin"}, schema.Columns[5])
+
+	read := func(column string, values any) {
+		file, err := os.Open(filepath.Join(dir, column+".bin"))
+		require.NoError(t, err)
+		defer file.Close()
+
+		require.NoError(t, binary.Read(file, binary.LittleEndian, values))
+	}
+
+	wagers := make([]int64, schema.Rows)
+	read("wager", wagers)
+	require.Equal(t, []int64{100, 10000, 100}, wagers)
+
+	triggered := make([]uint8, schema.Rows)
+	read("bonus_triggered", triggered)
+	require.Equal(t, []uint8{0, 1, 0}, triggered)
+
+	features := make([]int32, schema.Rows)
+	read("feature", features)
+	require.Equal(t, []int32{0, 1, 0}, features)
+
+	// every column has exactly the rows of the schema
+	for _, column := range schema.Columns {
+		info, err := os.Stat(filepath.Join(dir, column.File))
+		require.NoError(t, err)
+
+		size := map[string]int64{"int64": 8, "int32": 4, "uint8": 1}[column.Type]
+		require.Equal(t, schema.Rows*size, info.Size(), column.Name)
+	}
+}
+
+func TestNewSpinDump(t *testing.T) {
+	dump, err := newSpinDump(&SimulatorConfig{})
+	require.NoError(t, err)
+	require.Nil(t, dump)
+
+	_, err = newSpinDump(&SimulatorConfig{SpinDump: "parquet", ReportPath: t.TempDir()})
+	require.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrJobNotFound   = errors.New("simulation job not found")
+	ErrJobNotRunning = errors.New("simulation job is not running")
+)
+
+// SimulationJobStore keeps simulation jobs, the running ones are kept by SimulatorService and saved on status changes.
+type SimulationJobStore interface {
+	Save(job *SimulationJob) error
+	Get(id string) (*SimulationJob, error)
+	List() ([]*SimulationJob, error)
+}
+
+// SimulatorJobsConfig enables the file job store, jobs are kept in memory without it.
+type SimulatorJobsConfig struct {
+	Path string
+	// Instance is the stable name of the replica if the store is shared, a replica fails only its own jobs
+	// interrupted by the restart. Empty means the host name.
+	Instance string
+}
+
+// InstanceName returns the configured instance or the host name.
+func (c *SimulatorJobsConfig) InstanceName() string {
+	if c != nil && c.Instance != "" {
+		return c.Instance
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		zap.S().Warnf("can not get host name for simulation jobs: %v", err)
+	}
+
+	return host
+}
+
+func NewSimulationJobStore(cfg *SimulatorJobsConfig) (SimulationJobStore, error) {
+	if cfg != nil && cfg.Path != "" {
+		return NewFileJobStore(cfg.Path)
+	}
+
+	return NewMemoryJobStore(), nil
+}
+
+type memoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]SimulationJob
+}
+
+func NewMemoryJobStore() SimulationJobStore {
+	return &memoryJobStore{jobs: map[string]SimulationJob{}}
+}
+
+func (m *memoryJobStore) Save(job *SimulationJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobs[job.ID] = *job
+
+	return nil
+}
+
+func (m *memoryJobStore) Get(id string) (*SimulationJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	return &job, nil
+}
+
+func (m *memoryJobStore) List() ([]*SimulationJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return lo.MapToSlice(m.jobs, func(_ string, job SimulationJob) *SimulationJob {
+		return &job
+	}), nil
+}
+
+// fileJobStore keeps every job in its own json file, so jobs survive restarts.
+type fileJobStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+func NewFileJobStore(dir string) (SimulationJobStore, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("can not create jobs directory: %w", err)
+	}
+
+	return &fileJobStore{dir: dir}, nil
+}
+
+func (f *fileJobStore) Save(job *SimulationJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tmp := f.path(job.ID) + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.path(job.ID))
+}
+
+func (f *fileJobStore) Get(id string) (*SimulationJob, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.read(f.path(id))
+}
+
+func (f *fileJobStore) List() ([]*SimulationJob, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	paths, err := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*SimulationJob, 0, len(paths))
+
+	for _, path := range paths {
+		job, err := f.read(path)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (f *fileJobStore) path(id string) string {
+	// job ids are uuids, the base keeps ids from the api inside the directory
+	return filepath.Join(f.dir, filepath.Base(strings.TrimSpace(id))+".json")
+}
+
+func (f *fileJobStore) read(path string) (*SimulationJob, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrJobNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var job SimulationJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("can not read job %s: %w", path, err)
+	}
+
+	return &job, nil
+}
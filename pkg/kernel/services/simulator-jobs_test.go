@@ -0,0 +1,202 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulationJobStore(t *testing.T) {
+	file, err := NewFileJobStore(t.TempDir())
+	require.NoError(t, err)
+
+	stores := map[string]SimulationJobStore{"memory": NewMemoryJobStore(), "file": file}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Get("missing")
+			require.ErrorIs(t, err, ErrJobNotFound)
+
+			job := &SimulationJob{ID: "a", Game: "test", Status: StatusRunning, CreatedAt: time.Now().UTC()}
+			require.NoError(t, store.Save(job))
+			require.NoError(t, store.Save(&SimulationJob{ID: "b", Status: StatusPending}))
+
+			// the saved job is a copy
+			job.Status = StatusCompleted
+
+			got, err := store.Get("a")
+			require.NoError(t, err)
+			require.Equal(t, StatusRunning, got.Status)
+			require.Equal(t, "test", got.Game)
+
+			require.NoError(t, store.Save(job))
+
+			got, err = store.Get("a")
+			require.NoError(t, err)
+			require.Equal(t, StatusCompleted, got.Status)
+
+			jobs, err := store.List()
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"a", "b"}, []string{jobs[0].ID, jobs[1].ID})
+		})
+	}
+}
+
+func TestFileJobStore_Path(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileJobStore(filepath.Join(dir, "jobs"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(&SimulationJob{ID: "../escaped"}))
+
+	_, err = os.Stat(filepath.Join(dir, "escaped.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	got, err := store.Get("../escaped")
+	require.NoError(t, err)
+	require.Equal(t, "../escaped", got.ID)
+
+	// the jobs survive the restart
+	reopened, err := NewFileJobStore(filepath.Join(dir, "jobs"))
+	require.NoError(t, err)
+
+	jobs, err := reopened.List()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+}
+
+func TestSimulatorService_FailInterruptedJobs(t *testing.T) {
+	store, err := NewFileJobStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, job := range []*SimulationJob{
+		{ID: "own", Status: StatusRunning, Instance: "a"},
+		{ID: "own-completed", Status: StatusCompleted, Instance: "a"},
+		{ID: "other", Status: StatusRunning, Instance: "b"},
+	} {
+		require.NoError(t, store.Save(job))
+	}
+
+	s := newTestSimulator(nil)
+	s.jobs, s.instance = store, "a"
+	s.failInterruptedJobs()
+
+	statuses := map[string]SimulationStatus{"own": StatusFailed, "own-completed": StatusCompleted, "other": StatusRunning}
+
+	for id, status := range statuses {
+		job, err := store.Get(id)
+		require.NoError(t, err)
+		require.Equal(t, status, job.Status, id)
+	}
+}
+
+// waitJob waits until the job is finished and returns the stored one.
+func waitJob(t *testing.T, s *SimulatorService, id string) *SimulationJob {
+	require.Eventually(t, func() bool {
+		s.jobsMux.RLock()
+		defer s.jobsMux.RUnlock()
+
+		_, running := s.running[id]
+
+		return !running
+	}, 10*time.Second, time.Millisecond)
+
+	job, err := s.jobs.Get(id)
+	require.NoError(t, err)
+
+	return job
+}
+
+func TestSimulatorService_CreateSimulation(t *testing.T) {
+	var received atomic.Pointer[SimulatorResult]
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result SimulatorResult
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&result))
+		received.Store(&result)
+	}))
+	defer server.Close()
+
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 3)})
+	s.instance = "a"
+
+	id, err := s.CreateSimulation(&SimulatorConfig{
+		GameName: "test", Spins: 1000, Wager: 100, Workers: 2, CallbackURL: server.URL,
+	}, "96", "high")
+	require.NoError(t, err)
+
+	job := waitJob(t, s, id)
+	require.Equal(t, StatusCompleted, job.Status)
+	require.Equal(t, int64(1000), job.Done)
+	require.Equal(t, "a", job.Instance)
+	require.Equal(t, "1000", job.Result.Count)
+	require.Empty(t, job.CallbackError)
+
+	require.Equal(t, id, received.Load().JobID)
+	require.Equal(t, string(StatusCompleted), received.Load().Status)
+}
+
+func TestSimulatorService_CallbackError(t *testing.T) {
+	var attempts atomic.Int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 3)})
+
+	id, err := s.CreateSimulation(&SimulatorConfig{
+		GameName: "test", Spins: 100, Wager: 100, Workers: 1, CallbackURL: server.URL,
+	}, "96", "high")
+	require.NoError(t, err)
+
+	// the completed job keeps its status and result
+	job := waitJob(t, s, id)
+	require.Equal(t, StatusCompleted, job.Status)
+	require.NotNil(t, job.Result)
+	require.Empty(t, job.Error)
+	require.NotEmpty(t, job.CallbackError)
+	require.Equal(t, int64(3), attempts.Load())
+}
+
+func TestSimulatorService_CancelJob(t *testing.T) {
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 3)})
+
+	require.ErrorIs(t, s.CancelJob("missing"), ErrJobNotFound)
+
+	id, err := s.CreateSimulation(&SimulatorConfig{
+		GameName: "test", Spins: 1_000_000_000, Wager: 100, Workers: 1,
+	}, "96", "high")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		job, err := s.GetJob(id)
+		require.NoError(t, err)
+
+		return job.Status == StatusRunning
+	}, 10*time.Second, time.Millisecond)
+
+	require.NoError(t, s.CancelJob(id))
+
+	job := waitJob(t, s, id)
+	require.Equal(t, StatusCancelled, job.Status)
+	require.NotNil(t, job.CompletedAt)
+	require.Less(t, job.Done, job.Spins)
+
+	require.ErrorIs(t, s.CancelJob(id), ErrJobNotRunning)
+
+	jobs, err := s.ListJobs()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+}
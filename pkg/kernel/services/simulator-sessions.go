@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/entities"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"bitbucket.org/play-workspace/base-slot-server/utils"
+	"github.com/samber/lo"
+)
+
+const (
+	FixedBetStrategy      = "fixed"
+	MartingaleBetStrategy = "martingale"
+	RandomBetStrategy     = "random"
+
+	survivalPoints = 100
+)
+
+// SessionConfig switches the simulator to the session mode: SimulatorConfig.Spins is the max session length,
+// SimulatorConfig.Wager is the base wager of the strategy.
+type SessionConfig struct {
+	Players      int
+	StartBalance int64
+	Strategy     string  // fixed (default), martingale or random
+	WagerLevels  []int64 // levels for martingale and random strategies
+
+	// GambleSteps is the number of double ups played after every base game win, 0 means no gamble.
+	GambleSteps int
+	GambleType  string // empty means engine.ColorGamble
+	// TargetMultiplier is the balance multiplier counted as the player's goal, 0 means 2 (doubling).
+	TargetMultiplier float64
+}
+
+type session struct {
+	Spins        int64
+	Busted       bool
+	TargetHit    bool
+	FirstBonus   int64 // spin of the first bonus, 0 if there was no bonus
+	FinalBalance int64
+	MaxBalance   int64
+}
+
+type SessionSimulationResult struct {
+	Game         string
+	Seed         *uint64
+	Players      int
+	StartBalance int64
+	MaxSpins     int64
+	Strategy     string
+
+	Sessions []session
+}
+
+func (s *SimulatorService) SimulateSessions(cfg *SimulatorConfig) (*SessionSimulationResult, error) {
+	sc := cfg.Sessions
+
+	if sc.Players <= 0 || sc.StartBalance <= 0 || cfg.Spins <= 0 || cfg.Wager <= 0 {
+		return nil, errors.New("players, start balance, spins and wager must be positive")
+	}
+
+	if sc.Strategy != FixedBetStrategy && sc.Strategy != "" && len(sc.WagerLevels) == 0 {
+		return nil, fmt.Errorf("strategy %s requires wager levels", sc.Strategy)
+	}
+
+	if cfg.Seed != nil {
+		if _, ok := s.boot.SpinFactory.(engine.SeedableSpinFactory); !ok {
+			return nil, fmt.Errorf("spin factory %T does not implement engine.SeedableSpinFactory", s.boot.SpinFactory)
+		}
+	}
+
+	res := &SessionSimulationResult{
+		Game:         cfg.GameName,
+		Seed:         cfg.Seed,
+		Players:      sc.Players,
+		StartBalance: sc.StartBalance,
+		MaxSpins:     cfg.Spins,
+		Strategy:     lo.Ternary(sc.Strategy == "", FixedBetStrategy, sc.Strategy),
+		Sessions:     make([]session, sc.Players),
+	}
+
+	// returning on error stops the producer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		workers = max(cfg.Workers, 1)
+		inputCh = make(chan int, workers)
+		errCh   = make(chan error, workers)
+		wg      = new(sync.WaitGroup)
+	)
+
+	go func() {
+		defer close(inputCh)
+
+		for i := 0; i < sc.Players; i++ {
+			select {
+			case inputCh <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for player := range inputCh {
+				factory := s.boot.SpinFactory
+
+				if cfg.Seed != nil {
+					factory = factory.(engine.SeedableSpinFactory).WithRngClient(
+						rng.NewSeededClient(rng.DeriveSeed(*cfg.Seed, uint64(player))))
+				}
+
+				played, err := s.playSession(cfg, factory)
+				if err != nil {
+					errCh <- err
+					cancel()
+
+					return
+				}
+
+				res.Sessions[player] = played
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *SimulatorService) playSession(cfg *SimulatorConfig, factory engine.SpinFactory) (session, error) {
+	sc := cfg.Sessions
+	balance := sc.StartBalance
+	target := int64(float64(sc.StartBalance) * lo.Ternary(sc.TargetMultiplier > 0, sc.TargetMultiplier, 2))
+	wager := cfg.Wager
+
+	played := session{MaxBalance: balance}
+
+	var prevSpin engine.Spin
+
+	for played.Spins < cfg.Spins {
+		purchase, err := s.boot.Price(wager, cfg.GenerateParams, engine.PricingRules{
+			BuyBonus:         true,
+			DoubleChance:     true,
+			AllJurisdictions: true,
+		})
+		if err != nil {
+			return played, err
+		}
+
+		if balance < purchase.Wager() {
+			played.Busted = true
+
+			break
+		}
+
+		ctx := engine.Context{Context: context.Background(), LastSpin: prevSpin, Purchase: purchase}
+
+		spin, _, err := factory.Generate(ctx, wager, cfg.GenerateParams)
+		if err != nil {
+			return played, err
+		}
+
+		if s.keepGenerateFunc != nil {
+			if spin, err = s.keepGenerateFunc(ctx, spin, factory); err != nil {
+				return played, err
+			}
+		}
+
+		if spin, _, err = s.applyChoices(ctx, spin, factory, played.Spins); err != nil {
+			return played, err
+		}
+
+		prevSpin = spin
+		played.Spins++
+
+		maxWin := entities.ApplyMaxWin(spin, s.boot.MaxWin(wager))
+
+		// the spin which reached the max win can not be gambled, as in the game flow
+		if maxWin == nil {
+			if err := s.gamble(factory.GetRngClient(), spin, sc, wager); err != nil {
+				return played, err
+			}
+		}
+
+		award := maxWin.TotalAwardWithGambling(spin)
+		balance += award - spin.Wager()
+
+		if spin.BonusTriggered() && played.FirstBonus == 0 {
+			played.FirstBonus = played.Spins
+		}
+
+		if balance >= target {
+			played.TargetHit = true
+		}
+
+		played.MaxBalance = max(played.MaxBalance, balance)
+
+		if wager, err = nextWager(factory.GetRngClient(), sc, cfg.Wager, wager, award >= spin.Wager()); err != nil {
+			return played, err
+		}
+	}
+
+	played.FinalBalance = balance
+
+	return played, nil
+}
+
+// gamble plays double ups with random picks while the player wins.
+func (s *SimulatorService) gamble(rand rng.Client, spin engine.Spin, sc *SessionConfig, wager int64) error {
+	steps := sc.GambleSteps
+
+	gamble := spin.GetGamble()
+	if steps <= 0 || gamble == nil || spin.BaseAward() == 0 || spin.BonusTriggered() {
+		return nil
+	}
+
+	cfg := engine.GambleConfig{DoubleUpLimit: steps, MaxWin: s.boot.MaxWin(wager)}
+
+	gambleType, err := engine.GetGambleType(sc.GambleType)
+	if err != nil {
+		return err
+	}
+
+	for gamble.Len() < steps && !gamble.Closed() {
+		pick, err := randomPick(rand, gambleType)
+		if err != nil {
+			return err
+		}
+
+		params := engine.GambleParams{GamblePick: &pick, GambleType: sc.GambleType}
+
+		if err := gamble.PlayWithConfig(rand, spin, params, nil, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// randomPick draws one of the picks accepted by the gamble type, so it works with game specific gamble types too.
+func randomPick(rand rng.Client, gambleType engine.GambleType) (uint64, error) {
+	const maxPick = 16
+
+	var picks []uint64
+
+	for pick := uint64(0); pick < maxPick; pick++ {
+		if gambleType.ValidatePick(pick) == nil {
+			picks = append(picks, pick)
+		}
+	}
+
+	if len(picks) == 0 {
+		return 0, errors.New("gamble type accepts no picks")
+	}
+
+	index, err := rand.Rand(uint64(len(picks)))
+	if err != nil {
+		return 0, err
+	}
+
+	return picks[index], nil
+}
+
+func nextWager(rand rng.Client, sc *SessionConfig, base, current int64, won bool) (int64, error) {
+	switch sc.Strategy {
+	case MartingaleBetStrategy:
+		if won {
+			return base, nil
+		}
+
+		levels := append([]int64(nil), sc.WagerLevels...)
+		sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+		next, ok := lo.Find(levels, func(item int64) bool { return item >= current*2 })
+		if !ok {
+			next = levels[len(levels)-1]
+		}
+
+		return next, nil
+	case RandomBetStrategy:
+		index, err := rand.Rand(uint64(len(sc.WagerLevels)))
+		if err != nil {
+			return 0, err
+		}
+
+		return sc.WagerLevels[index], nil
+	default:
+		return base, nil
+	}
+}
+
+type SessionSummaryView struct {
+	Game         string `json:"game" xlsx:"Game"`
+	Seed         string `json:"seed" xlsx:"Seed"`
+	Players      string `json:"players" xlsx:"Players"`
+	StartBalance string `json:"start_balance" xlsx:"Start Balance"`
+	MaxSpins     string `json:"max_spins" xlsx:"Max Session Spins"`
+	Strategy     string `json:"strategy" xlsx:"Bet Strategy"`
+
+	NewLine1 string `xlsx:""`
+
+	BustRate          string `json:"bust_rate" xlsx:"Bust Rate"`
+	AvgSpinsToBust    string `json:"avg_spins_to_bust" xlsx:"Avg Session Length Before Bust"`
+	MedianSpinsToBust string `json:"median_spins_to_bust" xlsx:"Median Session Length Before Bust"`
+	TargetRate        string `json:"target_rate" xlsx:"Target Balance Probability"`
+
+	NewLine2 string `xlsx:""`
+
+	BonusRate         string `json:"bonus_rate" xlsx:"Sessions With Bonus"`
+	AvgSpinsToBonus   string `json:"avg_spins_to_bonus" xlsx:"Avg Spins To First Bonus"`
+	AvgFinalBalance   string `json:"avg_final_balance" xlsx:"Avg Final Balance"`
+	AvgMaxBalance     string `json:"avg_max_balance" xlsx:"Avg Max Balance"`
+	SessionReturnRate string `json:"session_return_rate" xlsx:"Final To Start Balance"`
+}
+
+type SurvivalView struct {
+	Spins    string `json:"spins" xlsx:"Spins"`
+	Alive    string `json:"alive" xlsx:"Players Alive"`
+	Survival string `json:"survival" xlsx:"Survival"`
+}
+
+type SessionDistributionView struct {
+	From  string `json:"from" xlsx:"From"`
+	To    string `json:"to" xlsx:"To"`
+	Count string `json:"count" xlsx:"Sessions"`
+	Rate  string `json:"rate" xlsx:"Rate"`
+}
+
+func (r *SessionSimulationResult) Summary() *SessionSummaryView {
+	total := int64(len(r.Sessions))
+
+	busted := lo.Filter(r.Sessions, func(item session, _ int) bool { return item.Busted })
+	bonus := lo.Filter(r.Sessions, func(item session, _ int) bool { return item.FirstBonus > 0 })
+
+	bustSpins := lo.Map(busted, func(item session, _ int) int64 { return item.Spins })
+	sort.Slice(bustSpins, func(i, j int) bool { return bustSpins[i] < bustSpins[j] })
+
+	avg := func(values []int64) string {
+		if len(values) == 0 {
+			return "-"
+		}
+
+		return fmt.Sprintf("%.2f", float64(lo.Sum(values))/float64(len(values)))
+	}
+
+	median := "-"
+	if len(bustSpins) > 0 {
+		median = fmt.Sprint(bustSpins[len(bustSpins)/2])
+	}
+
+	finalBalances := lo.Map(r.Sessions, func(item session, _ int) int64 { return item.FinalBalance })
+
+	return &SessionSummaryView{
+		Game:         r.Game,
+		Seed:         lo.TernaryF(r.Seed != nil, func() string { return fmt.Sprint(*r.Seed) }, func() string { return "-" }),
+		Players:      fmt.Sprint(r.Players),
+		StartBalance: fmt.Sprint(r.StartBalance),
+		MaxSpins:     fmt.Sprint(r.MaxSpins),
+		Strategy:     r.Strategy,
+
+		BustRate:          countToRate(int64(len(busted)), total),
+		AvgSpinsToBust:    avg(bustSpins),
+		MedianSpinsToBust: median,
+		TargetRate:        countToRate(int64(lo.CountBy(r.Sessions, func(item session) bool { return item.TargetHit })), total),
+
+		BonusRate:         countToRate(int64(len(bonus)), total),
+		AvgSpinsToBonus:   avg(lo.Map(bonus, func(item session, _ int) int64 { return item.FirstBonus })),
+		AvgFinalBalance:   avg(finalBalances),
+		AvgMaxBalance:     avg(lo.Map(r.Sessions, func(item session, _ int) int64 { return item.MaxBalance })),
+		SessionReturnRate: floatWithPrecision(float64(lo.Sum(finalBalances)) / float64(r.StartBalance*total)),
+	}
+}
+
+// Survival is the share of the players that are not busted after N spins.
+func (r *SessionSimulationResult) Survival() []*SurvivalView {
+	step := max(r.MaxSpins/survivalPoints, 1)
+	total := int64(len(r.Sessions))
+
+	var views []*SurvivalView
+
+	for spins := int64(0); spins <= r.MaxSpins; spins += step {
+		alive := int64(lo.CountBy(r.Sessions, func(item session) bool { return !item.Busted || item.Spins > spins }))
+
+		views = append(views, &SurvivalView{
+			Spins:    fmt.Sprint(spins),
+			Alive:    fmt.Sprint(alive),
+			Survival: countToRate(alive, total),
+		})
+	}
+
+	return views
+}
+
+func (r *SessionSimulationResult) LengthDistribution() []*SessionDistributionView {
+	return distribution(lo.Map(r.Sessions, func(item session, _ int) int64 { return item.Spins }))
+}
+
+func (r *SessionSimulationResult) BalanceDistribution() []*SessionDistributionView {
+	return distribution(lo.Map(r.Sessions, func(item session, _ int) int64 { return item.FinalBalance }))
+}
+
+// distribution splits the values into 20 equal buckets.
+func distribution(values []int64) []*SessionDistributionView {
+	const buckets = 20
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	low, high := lo.Min(values), lo.Max(values)
+	width := int64(math.Ceil(float64(high-low+1) / buckets))
+
+	counts := make([]int64, buckets)
+	for _, v := range values {
+		counts[(v-low)/width]++
+	}
+
+	var views []*SessionDistributionView
+
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		views = append(views, &SessionDistributionView{
+			From:  fmt.Sprint(low + int64(i)*width),
+			To:    fmt.Sprint(low + int64(i+1)*width - 1),
+			Count: fmt.Sprint(count),
+			Rate:  countToRate(count, int64(len(values))),
+		})
+	}
+
+	return views
+}
+
+func (s *SimulatorService) SimulateSessionsV2(cfg *SimulatorConfig, rtp, volatility string) error {
+	restore := s.applyGameSpecificWrapper(cfg.GameName)
+	defer restore()
+
+	result, err := s.SimulateSessions(cfg)
+	if err != nil {
+		return fmt.Errorf("simulator error: %w", err)
+	}
+
+	reportPages := []utils.Page{{
+		Name:  "Sessions",
+		Table: utils.Transpose(utils.ExtractTable([]*SessionSummaryView{result.Summary()}, "xlsx")),
+	}, {
+		Name:  "Survival",
+		Table: utils.ExtractTable(result.Survival(), "xlsx"),
+	}, {
+		Name:  "Session Length",
+		Table: utils.ExtractTable(result.LengthDistribution(), "xlsx"),
+	}, {
+		Name:  "Final Balance",
+		Table: utils.ExtractTable(result.BalanceDistribution(), "xlsx"),
+	}}
+
+	return saveReport(cfg, reportPages, rtp, volatility)
+}
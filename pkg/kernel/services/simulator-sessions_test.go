@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/stretchr/testify/require"
+)
+
+// constFactory generates spins with the same base award.
+type constFactory struct {
+	*testFactory
+	award int64
+}
+
+func (f *constFactory) Generate(_ engine.Context, wager int64, _ interface{}) (engine.Spin, engine.RestoringIndexes, error) {
+	return &testSpin{Base: f.award, Wagered: wager}, nil, nil
+}
+
+func sessionConfig(players int, workers int) *SimulatorConfig {
+	return &SimulatorConfig{
+		GameName: "test",
+		Spins:    200,
+		Wager:    100,
+		Workers:  workers,
+		Sessions: &SessionConfig{Players: players, StartBalance: 1000},
+	}
+}
+
+func TestSimulatorService_SimulateSessions_Seed(t *testing.T) {
+	seed := uint64(7)
+
+	simulate := func(workers int) *SessionSimulationResult {
+		s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 3)})
+
+		cfg := sessionConfig(50, workers)
+		cfg.Seed = &seed
+
+		res, err := s.SimulateSessions(cfg)
+		require.NoError(t, err)
+
+		return res
+	}
+
+	// zero workers play with one worker
+	single := simulate(0)
+
+	require.Len(t, single.Sessions, 50)
+	require.Equal(t, single.Sessions, simulate(4).Sessions)
+}
+
+func TestSimulatorService_SimulateSessions_MaxWin(t *testing.T) {
+	factory := &constFactory{testFactory: newTestFactory(1, 1), award: 1000}
+
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: factory, MaxWinMultiplier: 3})
+
+	cfg := sessionConfig(1, 1)
+	cfg.Spins = 5
+	cfg.Sessions.GambleSteps = 1
+
+	res, err := s.SimulateSessions(cfg)
+	require.NoError(t, err)
+
+	// every spin pays 300 of 1000 and the capped spins are not gambled
+	require.Equal(t, session{Spins: 5, TargetHit: true, FinalBalance: 2000, MaxBalance: 2000}, res.Sessions[0])
+}
+
+func TestSimulatorService_SimulateSessions_Busted(t *testing.T) {
+	factory := &constFactory{testFactory: newTestFactory(1, 1)}
+
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: factory})
+
+	res, err := s.SimulateSessions(sessionConfig(2, 1))
+	require.NoError(t, err)
+
+	for _, played := range res.Sessions {
+		require.Equal(t, session{Spins: 10, Busted: true, MaxBalance: 1000}, played)
+	}
+
+	require.Equal(t, "100.000%", res.Summary().BustRate)
+}
+
+func TestSimulatorService_SimulateSessions_Error(t *testing.T) {
+	for _, workers := range []int{0, 1, 4} {
+		factory := newTestFactory(1, 3)
+		factory.failAt = 3
+
+		s := newTestSimulator(&engine.Bootstrap{SpinFactory: factory})
+
+		_, err := s.SimulateSessions(sessionConfig(100, workers))
+		require.ErrorIs(t, err, errTestGenerate)
+	}
+}
+
+func TestSimulatorService_SimulateSessions_Validation(t *testing.T) {
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 3)})
+
+	cfg := sessionConfig(0, 1)
+	_, err := s.SimulateSessions(cfg)
+	require.Error(t, err)
+
+	cfg = sessionConfig(1, 1)
+	cfg.Sessions.Strategy = MartingaleBetStrategy
+	_, err = s.SimulateSessions(cfg)
+	require.Error(t, err)
+}
+
+func TestNextWager(t *testing.T) {
+	martingale := &SessionConfig{Strategy: MartingaleBetStrategy, WagerLevels: []int64{800, 100, 200, 400}}
+
+	tests := []struct {
+		name    string
+		current int64
+		won     bool
+		want    int64
+	}{
+		{name: "won", current: 400, won: true, want: 100},
+		{name: "lost", current: 100, want: 200},
+		{name: "lost on max level", current: 800, want: 800},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextWager(nil, martingale, 100, tt.current, tt.won)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := nextWager(nil, &SessionConfig{}, 100, 400, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), got)
+}
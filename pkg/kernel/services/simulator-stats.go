@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/samber/lo"
+)
+
+// histogramBounds are the bounds of the win distribution buckets in wager multipliers,
+// the first bucket is for the spins without win, the last one is for the wins over the last bound.
+var histogramBounds = []float64{0, 1, 2, 5, 10, 20, 50, 100, 500, 1000, 5000}
+
+type symbolKey struct {
+	Symbol string
+	Length int
+}
+
+// Breakdown aggregates engine.SimulationStats of the spins and the win distribution of all the spins.
+// The views of a nil Breakdown are empty.
+type Breakdown struct {
+	Symbols   map[symbolKey]*SymbolStat
+	Triggers  map[string]int64 // feature -> triggers
+	Histogram []int64          // bucket -> spins, see histogramBounds
+}
+
+type SymbolStat struct {
+	Count int64
+	Award *big.Int
+}
+
+func newBreakdown() *Breakdown {
+	return &Breakdown{
+		Symbols:   map[symbolKey]*SymbolStat{},
+		Triggers:  map[string]int64{},
+		Histogram: make([]int64, len(histogramBounds)+1),
+	}
+}
+
+func (b *Breakdown) add(stats []engine.Stat, award, wager int64) {
+	for _, stat := range stats {
+		switch stat.Kind {
+		case engine.SymbolWinStat:
+			key := symbolKey{Symbol: stat.Tag, Length: stat.Length}
+
+			symbol, ok := b.Symbols[key]
+			if !ok {
+				symbol = &SymbolStat{Award: new(big.Int)}
+				b.Symbols[key] = symbol
+			}
+
+			symbol.Count++
+			symbol.Award.Add(symbol.Award, big.NewInt(stat.Award))
+		case engine.FeatureTriggerStat:
+			b.Triggers[stat.Tag]++
+		}
+	}
+
+	if award == 0 {
+		b.Histogram[0]++
+
+		return
+	}
+
+	multiplier := float64(award) / float64(wager)
+
+	b.Histogram[sort.Search(len(histogramBounds), func(i int) bool { return histogramBounds[i] > multiplier })]++
+}
+
+type SymbolView struct {
+	Symbol string `json:"symbol" xlsx:"Symbol"`
+	Length string `json:"length" xlsx:"Length"`
+	Count  string `json:"count" xlsx:"Count"`
+	Rate   string `json:"rate" xlsx:"Hit Rate"`
+	Award  string `json:"award" xlsx:"Award"`
+	RTP    string `json:"rtp" xlsx:"RTP"`
+}
+
+type TriggerView struct {
+	Feature   string `json:"feature" xlsx:"Feature"`
+	Count     string `json:"count" xlsx:"Count"`
+	Rate      string `json:"rate" xlsx:"Rate"`
+	Frequency string `json:"frequency" xlsx:"1 in N Spins"`
+}
+
+type HistogramView struct {
+	Bucket string `json:"bucket" xlsx:"Win (x Wager)"`
+	Count  string `json:"count" xlsx:"Count"`
+	Rate   string `json:"rate" xlsx:"Rate"`
+}
+
+func (b *Breakdown) symbolViews(count int64, spent *big.Int) []*SymbolView {
+	if b == nil {
+		return nil
+	}
+
+	keys := lo.Keys(b.Symbols)
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Symbol == keys[j].Symbol {
+			return keys[i].Length < keys[j].Length
+		}
+
+		return keys[i].Symbol < keys[j].Symbol
+	})
+
+	return lo.Map(keys, func(item symbolKey, _ int) *SymbolView {
+		stat := b.Symbols[item]
+		rtp, _ := new(big.Float).Quo(new(big.Float).SetInt(stat.Award), new(big.Float).SetInt(spent)).Float64()
+
+		return &SymbolView{
+			Symbol: item.Symbol,
+			Length: fmt.Sprint(item.Length),
+			Count:  fmt.Sprint(stat.Count),
+			Rate:   countToRate(stat.Count, count),
+			Award:  stat.Award.String(),
+			RTP:    floatWithPrecision(rtp),
+		}
+	})
+}
+
+func (b *Breakdown) triggerViews(count int64) []*TriggerView {
+	if b == nil {
+		return nil
+	}
+
+	features := lo.Keys(b.Triggers)
+	sort.Strings(features)
+
+	return lo.Map(features, func(item string, _ int) *TriggerView {
+		triggers := b.Triggers[item]
+
+		return &TriggerView{
+			Feature:   item,
+			Count:     fmt.Sprint(triggers),
+			Rate:      countToRate(triggers, count),
+			Frequency: fmt.Sprintf("%.2f", float64(count)/float64(triggers)),
+		}
+	})
+}
+
+func (b *Breakdown) histogramViews(count int64) []*HistogramView {
+	if b == nil {
+		return nil
+	}
+
+	return lo.Map(b.Histogram, func(item int64, i int) *HistogramView {
+		var bucket string
+
+		switch {
+		case i == 0:
+			bucket = "0"
+		case i == 1:
+			bucket = fmt.Sprintf("(0, %v)", histogramBounds[i])
+		case i == len(histogramBounds):
+			bucket = fmt.Sprintf("[%v, +inf)", histogramBounds[i-1])
+		default:
+			bucket = fmt.Sprintf("[%v, %v)", histogramBounds[i-1], histogramBounds[i])
+		}
+
+		return &HistogramView{Bucket: bucket, Count: fmt.Sprint(item), Rate: countToRate(item, count)}
+	})
+}
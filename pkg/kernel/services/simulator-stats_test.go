@@ -0,0 +1,99 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestResult returns the result of count spins with the given awards as Simulate does.
+func newTestResult(wager int64, awards ...int64) *SimulationResult {
+	res := &SimulationResult{
+		Wager: wager,
+		Count: int64(len(awards)),
+
+		BaseAward:  new(big.Int),
+		BonusAward: new(big.Int),
+		Award:      new(big.Int),
+		Spent:      big.NewInt(wager * int64(len(awards))),
+
+		BaseAwardSquareSum:  new(big.Int),
+		BonusAwardSquareSum: new(big.Int),
+		AwardSquareSum:      new(big.Int),
+
+		BaseAwardStandardDeviation:  new(big.Float),
+		BonusAwardStandardDeviation: new(big.Float),
+		AwardStandardDeviation:      new(big.Float),
+		Volatility:                  new(big.Float),
+
+		Features:  map[string]*FeatureResult{},
+		Choices:   map[string]*FeatureResult{},
+		Breakdown: newBreakdown(),
+	}
+
+	for _, award := range awards {
+		res.BaseAward.Add(res.BaseAward, big.NewInt(award))
+		res.Award.Add(res.Award, big.NewInt(award))
+		res.AwardSquareSum.Add(res.AwardSquareSum, big.NewInt(award*award))
+	}
+
+	return res
+}
+
+func TestBreakdown_Add(t *testing.T) {
+	b := newBreakdown()
+
+	b.add([]engine.Stat{
+		engine.NewSymbolWinStat("A", 3, 50),
+		engine.NewSymbolWinStat("A", 3, 50),
+		engine.NewSymbolWinStat("B", 5, 100),
+		engine.NewFeatureTriggerStat("free_spins"),
+	}, 200, 100)
+	b.add([]engine.Stat{engine.NewSymbolWinStat("A", 4, 20)}, 20, 100)
+	b.add(nil, 0, 100)
+
+	require.Equal(t, map[symbolKey]*SymbolStat{
+		{Symbol: "A", Length: 3}: {Count: 2, Award: big.NewInt(100)},
+		{Symbol: "A", Length: 4}: {Count: 1, Award: big.NewInt(20)},
+		{Symbol: "B", Length: 5}: {Count: 1, Award: big.NewInt(100)},
+	}, b.Symbols)
+	require.Equal(t, map[string]int64{"free_spins": 1}, b.Triggers)
+
+	views := b.symbolViews(4, big.NewInt(400))
+	require.Equal(t, []string{"A", "A", "B"}, lo.Map(views, func(item *SymbolView, _ int) string { return item.Symbol }))
+	require.Equal(t, &SymbolView{Symbol: "A", Length: "3", Count: "2", Rate: "50.000%", Award: "100", RTP: "25.000"}, views[0])
+
+	require.Equal(t, []*TriggerView{{Feature: "free_spins", Count: "1", Rate: "25.000%", Frequency: "4.00"}}, b.triggerViews(4))
+}
+
+func TestBreakdown_Histogram(t *testing.T) {
+	b := newBreakdown()
+
+	for _, award := range []int64{0, 0, 50, 100, 150, 999, 1000, 200000, 1000000} {
+		b.add(nil, award, 100)
+	}
+
+	require.Equal(t, []int64{2, 1, 2, 0, 1, 1, 0, 0, 0, 0, 1, 1}, b.Histogram)
+
+	views := b.histogramViews(9)
+	require.Len(t, views, len(histogramBounds)+1)
+	require.Equal(t, &HistogramView{Bucket: "0", Count: "2", Rate: "22.222%"}, views[0])
+	require.Equal(t, "(0, 1)", views[1].Bucket)
+	require.Equal(t, "[1, 2)", views[2].Bucket)
+	require.Equal(t, "[1000, 5000)", views[10].Bucket)
+	require.Equal(t, "[5000, +inf)", views[11].Bucket)
+}
+
+func TestSimulationResult_View_NoBreakdown(t *testing.T) {
+	res := newTestResult(100, 0, 200)
+	res.Breakdown = nil
+
+	view := res.View()
+	require.Empty(t, view.Symbols)
+	require.Empty(t, view.Triggers)
+	require.Empty(t, view.Histogram)
+	require.Equal(t, "2", view.Count)
+}
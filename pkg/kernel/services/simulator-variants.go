@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/utils"
+	"github.com/samber/lo"
+)
+
+var ErrNoBootstrapFactory = errors.New("bootstrap factory is not set, variants can not be simulated")
+
+// SimulationVariant is one game configuration of the multi-variant simulation.
+type SimulationVariant struct {
+	RTP            string
+	Volatility     string
+	GenerateParams interface{} // nil means SimulatorConfig.GenerateParams
+}
+
+// BootstrapFactory builds a fresh game bootstrap for the rtp and volatility, the app wraps its GameBootstrapV2 into it.
+type BootstrapFactory func(rtp, volatility string) (*engine.Bootstrap, error)
+
+type VariantResult struct {
+	Variant SimulationVariant
+	Result  *SimulationResult
+}
+
+type VariantView struct {
+	RTP        string `json:"rtp" xlsx:"Configured RTP"`
+	Volatility string `json:"volatility" xlsx:"Configured Volatility"`
+	Params     string `json:"params" xlsx:"Generate Params"`
+
+	Count           string  `json:"count" xlsx:"Count"`
+	SimulatedRTP    string  `json:"simulated_rtp" xlsx:"RTP"`
+	RTPBaseGame     string  `json:"rtp_base_game" xlsx:"RTP Base Game"`
+	RTPBonusGame    string  `json:"rtp_bonus_game" xlsx:"RTP Bonus Game"`
+	VolatilityIndex float64 `json:"volatility_index" xlsx:"Volatility Index"`
+	HitRate         string  `json:"hit_rate" xlsx:"Hit Rate"`
+	BonusGameRate   string  `json:"bonus_game_rate" xlsx:"Bonus Game Rate"`
+	MaxWinRate      string  `json:"max_win_rate" xlsx:"Max Win Rate"`
+	MaxExposure     string  `json:"max_exposure" xlsx:"Max Exposure"`
+
+	Result *SimulationView `json:"result" xlsx:"-"`
+}
+
+func (s *SimulatorService) WithBootstrapFactory(f BootstrapFactory) *SimulatorService {
+	s.bootFactory = f
+
+	return s
+}
+
+// variants returns the configured variants or the single variant of the given rtp and volatility.
+func variants(cfg *SimulatorConfig, rtp, volatility string) []SimulationVariant {
+	if len(cfg.Variants) > 0 {
+		return cfg.Variants
+	}
+
+	return []SimulationVariant{{RTP: rtp, Volatility: volatility}}
+}
+
+// SimulateVariants runs the variants concurrently, every variant has its own bootstrap and cfg.Workers workers.
+// onProgress is optional, it gets the number of simulated spins of all variants.
+func (s *SimulatorService) SimulateVariants(ctx context.Context, cfg *SimulatorConfig, variants []SimulationVariant,
+	onProgress func(done int64),
+) ([]*VariantResult, error) {
+	runners := make([]*SimulatorService, len(variants))
+
+	for i, variant := range variants {
+		boot, err := s.variantBootstrap(variant, len(variants))
+		if err != nil {
+			return nil, err
+		}
+
+		if runners[i], err = s.variantRunner(boot, cfg, variant, i, len(variants)); err != nil {
+			return nil, err
+		}
+	}
+
+	// a failed variant stops the others
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results = make([]*VariantResult, len(variants))
+		errs    = make([]error, len(variants))
+		done    = make([]int64, len(variants))
+
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for i, runner := range runners {
+		if onProgress != nil {
+			runner.onProgress = func(spins int64) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				done[i] = spins
+				onProgress(lo.Sum(done))
+			}
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			result, err := runner.Simulate(runCtx, cfg.GameName, cfg.Spins, cfg.Wager, cfg.Workers)
+			if err != nil {
+				errs[i] = fmt.Errorf("variant %s %s: %w", variants[i].RTP, variants[i].Volatility, err)
+				cancel()
+
+				return
+			}
+
+			results[i] = &VariantResult{Variant: variants[i], Result: result}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// variants stopped by the failed one are not reported
+	if err := errors.Join(lo.Reject(errs, func(item error, _ int) bool { return errors.Is(item, context.Canceled) })...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *SimulatorService) variantBootstrap(variant SimulationVariant, count int) (*engine.Bootstrap, error) {
+	if s.bootFactory == nil {
+		if count > 1 {
+			return nil, ErrNoBootstrapFactory
+		}
+
+		return s.boot, nil
+	}
+
+	return s.bootFactory(variant.RTP, variant.Volatility)
+}
+
+// variantRunner returns a service that simulates the variant without touching the state of s,
+// so the variants can run concurrently.
+func (s *SimulatorService) variantRunner(boot *engine.Bootstrap, cfg *SimulatorConfig, variant SimulationVariant,
+	index, count int,
+) (*SimulatorService, error) {
+	keepGenerate := s.keepGenerateFunc
+	if gameWrapper, exists := s.gameWrappers[cfg.GameName]; exists {
+		keepGenerate = gameWrapper
+	}
+
+	parts := []string{variant.RTP, variant.Volatility}
+	if count > 1 {
+		parts = append(parts, fmt.Sprintf("variant-%d", index+1))
+	}
+
+	dump, err := newSpinDump(cfg, parts...)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.choicePolicyFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SimulatorService{
+		boot:             boot,
+		generateParams:   lo.Ternary(variant.GenerateParams != nil, variant.GenerateParams, cfg.GenerateParams),
+		keepGenerateFunc: keepGenerate,
+		checkpointEvery:  cfg.CheckpointEvery,
+		targetPrecision:  cfg.TargetPrecision,
+		seed:             cfg.Seed,
+		dump:             dump,
+		choicePolicy:     policy,
+	}, nil
+}
+
+func (r *VariantResult) View() *VariantView {
+	view := r.Result.View()
+
+	params, _ := json.Marshal(r.Variant.GenerateParams)
+
+	return &VariantView{
+		RTP:        r.Variant.RTP,
+		Volatility: r.Variant.Volatility,
+		Params:     lo.Ternary(r.Variant.GenerateParams != nil, string(params), "-"),
+
+		Count:           view.Count,
+		SimulatedRTP:    view.RTP,
+		RTPBaseGame:     view.RTPBaseGame,
+		RTPBonusGame:    view.RTPBonusGame,
+		VolatilityIndex: view.Volatility,
+		HitRate:         view.AwardRate,
+		BonusGameRate:   view.BonusGameRate,
+		MaxWinRate:      view.MaxWinRate,
+		MaxExposure:     view.MaxExposure,
+
+		Result: view,
+	}
+}
+
+// comparisonPages is the combined report: the comparison table and the pages of the single run for every variant.
+func comparisonPages(views []*VariantView) []utils.Page {
+	pages := []utils.Page{{
+		Name:  "Comparison",
+		Table: utils.ExtractTable(views, "xlsx"),
+	}}
+
+	for i, view := range views {
+		for _, page := range simulationPages(view.Result) {
+			page.Name = fmt.Sprintf("Variant %d %s", i+1, page.Name)
+			pages = append(pages, page)
+		}
+	}
+
+	return pages
+}
+
+func (s *SimulatorService) SimulateVariantsV2(cfg *SimulatorConfig) error {
+	results, err := s.SimulateVariants(context.Background(), cfg, cfg.Variants, nil)
+	if err != nil {
+		return fmt.Errorf("simulator error: %w", err)
+	}
+
+	views := lo.Map(results, func(item *VariantResult, _ int) *VariantView { return item.View() })
+
+	return saveReport(cfg, comparisonPages(views), "comparison", "")
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+// testBootstrapFactory returns the bootstraps with rtp -> multipliers of the test factory,
+// the "fail" rtp fails on the 10th spin.
+func testBootstrapFactory(rtp, _ string) (*engine.Bootstrap, error) {
+	switch rtp {
+	case "fail":
+		factory := newTestFactory(1, 10)
+		factory.failAt = 10
+
+		return &engine.Bootstrap{SpinFactory: factory}, nil
+	case "unknown":
+		return nil, errTestGenerate
+	}
+
+	return &engine.Bootstrap{SpinFactory: newTestFactory(1, map[string]uint64{"94": 5, "96": 10, "98": 20}[rtp])}, nil
+}
+
+func newTestVariantsConfig(spins int64) *SimulatorConfig {
+	seed := uint64(42)
+
+	return &SimulatorConfig{GameName: "test", Spins: spins, Wager: 100, Workers: 2, Seed: &seed}
+}
+
+func TestSimulatorService_SimulateVariants(t *testing.T) {
+	s := newTestSimulator(nil).WithBootstrapFactory(testBootstrapFactory)
+	cfg := newTestVariantsConfig(1000)
+
+	variants := []SimulationVariant{{RTP: "94"}, {RTP: "96"}, {RTP: "98"}}
+
+	var progress atomic.Int64
+
+	results, err := s.SimulateVariants(context.Background(), cfg, variants, func(done int64) {
+		progress.Store(done)
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, int64(3000), progress.Load())
+
+	// every variant is simulated with its own bootstrap as the single run of it
+	for i, variant := range variants {
+		require.Equal(t, variant, results[i].Variant)
+
+		boot, err := testBootstrapFactory(variant.RTP, "")
+		require.NoError(t, err)
+
+		single := newTestSimulator(boot)
+		single.seed = cfg.Seed
+
+		want, err := single.Simulate(context.Background(), cfg.GameName, cfg.Spins, cfg.Wager, cfg.Workers)
+		require.NoError(t, err)
+		require.Equal(t, want.View(), results[i].Result.View())
+	}
+
+	require.NotEqual(t, results[0].Result.View().RTP, results[2].Result.View().RTP)
+}
+
+func TestSimulatorService_SimulateVariants_Errors(t *testing.T) {
+	s := newTestSimulator(nil).WithBootstrapFactory(testBootstrapFactory)
+
+	// the failed variant stops the others, only its error is reported
+	_, err := s.SimulateVariants(context.Background(), newTestVariantsConfig(1000000), []SimulationVariant{
+		{RTP: "94"}, {RTP: "fail", Volatility: "high"}, {RTP: "98"},
+	}, nil)
+	require.ErrorIs(t, err, errTestGenerate)
+	require.NotErrorIs(t, err, context.Canceled)
+	require.ErrorContains(t, err, "variant fail high")
+
+	_, err = s.SimulateVariants(context.Background(), newTestVariantsConfig(1000), []SimulationVariant{
+		{RTP: "94"}, {RTP: "unknown"},
+	}, nil)
+	require.ErrorIs(t, err, errTestGenerate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = s.SimulateVariants(ctx, newTestVariantsConfig(1000), []SimulationVariant{{RTP: "94"}, {RTP: "96"}}, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSimulatorService_SimulateVariants_NoBootstrapFactory(t *testing.T) {
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 10)})
+	cfg := newTestVariantsConfig(100)
+
+	_, err := s.SimulateVariants(context.Background(), cfg, []SimulationVariant{{RTP: "94"}, {RTP: "96"}}, nil)
+	require.ErrorIs(t, err, ErrNoBootstrapFactory)
+
+	// the single variant is simulated with the bootstrap of the service
+	results, err := s.SimulateVariants(context.Background(), cfg, []SimulationVariant{{RTP: "94"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "100", results[0].Result.View().Count)
+}
+
+func TestComparisonPages(t *testing.T) {
+	s := newTestSimulator(nil).WithBootstrapFactory(testBootstrapFactory)
+
+	results, err := s.SimulateVariants(context.Background(), newTestVariantsConfig(100), []SimulationVariant{
+		{RTP: "94", Volatility: "low"},
+		{RTP: "96", Volatility: "high", GenerateParams: map[string]int{"level": 2}},
+	}, nil)
+	require.NoError(t, err)
+
+	views := lo.Map(results, func(item *VariantResult, _ int) *VariantView { return item.View() })
+	require.Equal(t, "-", views[0].Params)
+	require.Equal(t, `{"level":2}`, views[1].Params)
+
+	pages := comparisonPages(views)
+	single := simulationPages(views[0].Result)
+
+	// the comparison table and every page of the single run per variant
+	require.Len(t, pages, 1+2*len(single))
+	require.Equal(t, "Comparison", pages[0].Name)
+	require.Len(t, pages[0].Table, 3)
+	require.Equal(t, []string{"94", "low", "-"}, pages[0].Table[1][:3])
+
+	for i, page := range single {
+		require.Equal(t, "Variant 1 "+page.Name, pages[1+i].Name)
+		require.Equal(t, page.Table, pages[1+i].Table)
+		require.Equal(t, "Variant 2 "+page.Name, pages[1+len(single)+i].Name)
+	}
+}
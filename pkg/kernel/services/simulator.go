@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -11,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"go.uber.org/zap"
 
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
 	"bitbucket.org/play-workspace/base-slot-server/utils"
 	"github.com/samber/lo"
 	"github.com/schollz/progressbar/v3"
@@ -30,16 +33,34 @@ const (
 	StatusRunning   SimulationStatus = "running"
 	StatusCompleted SimulationStatus = "completed"
 	StatusFailed    SimulationStatus = "failed"
+	StatusCancelled SimulationStatus = "cancelled"
 )
 
+func (s SimulationStatus) Final() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCancelled
+}
+
 type SimulationJob struct {
-	ID          string           `json:"id"`
-	Status      SimulationStatus `json:"status"`
-	Result      *SimulationView  `json:"result,omitempty"`
-	Error       string           `json:"error,omitempty"`
-	CallbackURL string           `json:"callback_url"`
-	CreatedAt   time.Time        `json:"created_at"`
-	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ID            string           `json:"id"`
+	Game          string           `json:"game"`
+	RTP           string           `json:"rtp"`
+	Volatility    string           `json:"volatility"`
+	Status        SimulationStatus `json:"status"`
+	Result        *SimulationView  `json:"result,omitempty"`
+	Variants      []*VariantView   `json:"variants,omitempty"` // results of the multi-variant simulation
+	Error         string           `json:"error,omitempty"`
+	CallbackURL   string           `json:"callback_url"`
+	CallbackError string           `json:"callback_error,omitempty"` // the result is not delivered, the status is kept
+	Instance      string           `json:"instance"`                 // replica running the job, see SimulatorJobsConfig
+	Spins         int64            `json:"spins"`
+	Done          int64            `json:"done"` // simulated spins, it is updated every percent
+	CreatedAt     time.Time        `json:"created_at"`
+	CompletedAt   *time.Time       `json:"completed_at,omitempty"`
+}
+
+type runningJob struct {
+	job    *SimulationJob
+	cancel context.CancelFunc
 }
 
 type SimulatorConfig struct {
@@ -50,6 +71,30 @@ type SimulatorConfig struct {
 	Workers        int
 	GenerateParams interface{}
 	CallbackURL    string
+
+	// CheckpointEvery is the number of spins between RTP convergence checkpoints, 0 means 1% of spins.
+	CheckpointEvery int64
+	// TargetPrecision is the RTP precision (0.001 is ±0.1%) to compute the number of spins needed for it.
+	TargetPrecision float64
+
+	// Seed makes the run reproducible with the seeded rng, nil means the rng of the spin factory.
+	// Seeded spins are played without the previous spin, so games with chain dependency can not be seeded.
+	Seed *uint64
+	// Sessions switches the simulator to the session mode, see SessionConfig.
+	Sessions *SessionConfig
+	// Variants are simulated concurrently with their own bootstraps and compared in one report.
+	Variants []SimulationVariant
+
+	// ReportFormat is xlsx (default), csv (a directory with a file per page) or json.
+	ReportFormat string
+	// SpinDump writes every simulated spin next to the report: csv or columnar, empty means no dump.
+	// Columnar is raw column files with schema.json, it is not Parquet.
+	SpinDump string
+
+	// ChoicePolicy picks the choices of engine.ChoiceSpin: random (default), fixed or all (every combination in turn).
+	ChoicePolicy string
+	// ChoiceIndex is the choice of the fixed policy.
+	ChoiceIndex int
 }
 
 type KeepGenerateWrapper func(engine.Context, engine.Spin, engine.SpinFactory) (engine.Spin, error)
@@ -59,18 +104,61 @@ type SimulatorService struct {
 	generateParams   interface{}
 	keepGenerate     bool
 	keepGenerateFunc KeepGenerateWrapper
-	jobs             map[string]*SimulationJob
+	checkpointEvery  int64
+	targetPrecision  float64
+	seed             *uint64
+	onProgress       func(done int64)
+	bootFactory      BootstrapFactory
+	dump             *spinDump
+	choicePolicy     ChoicePolicy
+	jobs             SimulationJobStore
+	running          map[string]*runningJob
 	jobsMux          sync.RWMutex
+	runMux           sync.Mutex // every job uses all of its workers, so jobs are run one by one
 	client           *http.Client
+	callbackBackoff  time.Duration
+	instance         string
 	gameWrappers     map[string]KeepGenerateWrapper
 }
 
-func NewSimulatorService() *SimulatorService {
-	return &SimulatorService{
-		boot:         engine.GetFromContainer(),
-		jobs:         make(map[string]*SimulationJob),
-		client:       &http.Client{Timeout: 30 * time.Second},
-		gameWrappers: make(map[string]KeepGenerateWrapper),
+// NewSimulatorService returns the service running the jobs as the instance, see SimulatorJobsConfig.Instance.
+func NewSimulatorService(jobs SimulationJobStore, instance string) *SimulatorService {
+	s := &SimulatorService{
+		boot:            engine.GetFromContainer(),
+		jobs:            jobs,
+		running:         make(map[string]*runningJob),
+		client:          &http.Client{Timeout: 30 * time.Second},
+		callbackBackoff: time.Second,
+		instance:        instance,
+		gameWrappers:    make(map[string]KeepGenerateWrapper),
+	}
+
+	s.failInterruptedJobs()
+
+	return s
+}
+
+// failInterruptedJobs marks jobs of the instance that were not finished before the restart as failed,
+// the jobs of other replicas sharing the store are kept.
+func (s *SimulatorService) failInterruptedJobs() {
+	jobs, err := s.jobs.List()
+	if err != nil {
+		zap.S().Errorf("can not list simulation jobs: %v", err)
+
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Status.Final() || job.Instance != s.instance {
+			continue
+		}
+
+		now := time.Now()
+		job.Status, job.Error, job.CompletedAt = StatusFailed, "interrupted by restart", &now
+
+		if err := s.jobs.Save(job); err != nil {
+			zap.S().Errorf("can not save simulation job %s: %v", job.ID, err)
+		}
 	}
 }
 
@@ -111,23 +199,20 @@ type SimulatorResult struct {
 	Result      *SimulationView `json:"result"`
 	RTP         string          `json:"rtp"`
 	Volatility  string          `json:"volatility"`
+	Variants    []*VariantView  `json:"variants,omitempty"`
 	Status      string          `json:"status"`
 	Error       string          `json:"error,omitempty"`
 	CompletedAt *time.Time      `json:"completed_at,omitempty"`
 }
 
-func (j *SimulationJob) toResult(rtp, volatility string) *SimulatorResult {
-	var gameName string
-	if j.Result != nil {
-		gameName = j.Result.Game
-	}
-
+func (j *SimulationJob) toResult() *SimulatorResult {
 	return &SimulatorResult{
 		JobID:       j.ID,
-		Game:        gameName,
+		Game:        j.Game,
 		Result:      j.Result,
-		RTP:         rtp,
-		Volatility:  volatility,
+		RTP:         j.RTP,
+		Volatility:  j.Volatility,
+		Variants:    j.Variants,
 		Status:      string(j.Status),
 		Error:       j.Error,
 		CompletedAt: j.CompletedAt,
@@ -135,70 +220,159 @@ func (j *SimulationJob) toResult(rtp, volatility string) *SimulatorResult {
 }
 
 func (s *SimulatorService) SimulateV2(cfg *SimulatorConfig, rtp, volatility string) error {
+	if cfg.Sessions != nil {
+		return s.SimulateSessionsV2(cfg, rtp, volatility)
+	}
+
+	if len(cfg.Variants) > 0 {
+		return s.SimulateVariantsV2(cfg)
+	}
+
 	restore := s.applyGameSpecificWrapper(cfg.GameName)
 	defer restore()
 
 	s.generateParams = cfg.GenerateParams
+	s.checkpointEvery, s.targetPrecision, s.seed = cfg.CheckpointEvery, cfg.TargetPrecision, cfg.Seed
+
+	dump, err := newSpinDump(cfg, rtp, volatility)
+	if err != nil {
+		return err
+	}
 
-	result, err := s.Simulate(cfg.GameName, cfg.Spins, cfg.Wager, cfg.Workers)
+	policy, err := s.choicePolicyFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	originalPolicy := s.choicePolicy
+	s.dump, s.choicePolicy = dump, policy
+
+	defer func() { s.dump, s.choicePolicy = nil, originalPolicy }()
+
+	result, err := s.Simulate(context.Background(), cfg.GameName, cfg.Spins, cfg.Wager, cfg.Workers)
 	if err != nil {
 		return fmt.Errorf("simulator error: %w", err)
 	}
 
-	reportPages := []utils.Page{{
+	return saveReport(cfg, simulationPages(result.View()), rtp, volatility)
+}
+
+func simulationPages(view *SimulationView) []utils.Page {
+	return []utils.Page{{
 		Name:  "Report",
-		Table: utils.Transpose(utils.ExtractTable([]*SimulationView{result.View()}, "xlsx")),
+		Table: utils.Transpose(utils.ExtractTable([]*SimulationView{view}, "xlsx")),
+	}, {
+		Name:  "Features",
+		Table: utils.ExtractTable(view.Features, "xlsx"),
+	}, {
+		Name:  "Choices",
+		Table: utils.ExtractTable(view.Choices, "xlsx"),
+	}, {
+		Name:  "Symbols",
+		Table: utils.ExtractTable(view.Symbols, "xlsx"),
+	}, {
+		Name:  "Feature Triggers",
+		Table: utils.ExtractTable(view.Triggers, "xlsx"),
+	}, {
+		Name:  "Win Distribution",
+		Table: utils.ExtractTable(view.Histogram, "xlsx"),
+	}, {
+		Name:  "Confidence Intervals",
+		Table: utils.ExtractTable(view.ConfidenceIntervals, "xlsx"),
+	}, {
+		Name:  "RTP Convergence",
+		Table: utils.ExtractTable(view.Convergence, "xlsx"),
 	}}
-
-	return saveReport(cfg, reportPages, rtp, volatility)
 }
 
 func (s *SimulatorService) CreateSimulation(cfg *SimulatorConfig, rtp, volatility string) (string, error) {
-	job := s.createJob(cfg)
+	job, ctx, err := s.createJob(cfg, rtp, volatility)
+	if err != nil {
+		return "", err
+	}
 
-	go func() {
-		s.updateJobStatus(job.ID, StatusRunning, nil)
-		initialResult := job.toResult(rtp, volatility)
-		initialResult.Game = cfg.GameName
+	go s.runJob(ctx, job, cfg)
 
-		restore := s.applyGameSpecificWrapper(cfg.GameName)
+	return job.ID, nil
+}
 
-		s.generateParams = cfg.GenerateParams
-		result, err := s.Simulate(cfg.GameName, cfg.Spins, cfg.Wager, cfg.Workers)
+func (s *SimulatorService) runJob(ctx context.Context, job *SimulationJob, cfg *SimulatorConfig) {
+	defer s.finishJob(job.ID)
 
-		restore()
+	s.simulateJob(ctx, job, cfg)
 
-		if err != nil {
-			s.updateJobStatus(job.ID, StatusFailed, fmt.Errorf("failed to simulate: %w", err))
-			failedResult := job.toResult(rtp, volatility)
-			failedResult.Game = cfg.GameName
-			_ = s.sendResult(cfg.CallbackURL, failedResult)
-			return
-		}
+	snapshot := s.snapshot(job)
+	if snapshot.CallbackURL == "" {
+		return
+	}
 
+	// the simulation is finished, so the failed delivery does not change the status
+	if err := s.notifyGameSimulator(snapshot); err != nil {
+		s.updateJob(job.ID, func(job *SimulationJob) {
+			job.CallbackError = fmt.Sprintf("failed to send final result: %v", err)
+		})
+	}
+}
+
+func (s *SimulatorService) simulateJob(ctx context.Context, job *SimulationJob, cfg *SimulatorConfig) {
+	s.runMux.Lock()
+	defer s.runMux.Unlock()
+
+	// the job was cancelled while it was waiting for the previous one
+	if ctx.Err() != nil {
+		s.updateJobStatus(job.ID, StatusCancelled, nil)
+
+		return
+	}
+
+	s.updateJobStatus(job.ID, StatusRunning, nil)
+
+	results, err := s.SimulateVariants(ctx, cfg, variants(cfg, job.RTP, job.Volatility), func(done int64) {
 		s.jobsMux.Lock()
-		job.Result = result.View()
+		job.Done = done
 		s.jobsMux.Unlock()
+	})
 
-		s.updateJobStatus(job.ID, StatusCompleted, nil)
-		completedResult := job.toResult(rtp, volatility)
+	switch {
+	case errors.Is(err, context.Canceled):
+		s.updateJobStatus(job.ID, StatusCancelled, nil)
+	case err != nil:
+		s.updateJobStatus(job.ID, StatusFailed, fmt.Errorf("failed to simulate: %w", err))
+	default:
+		views := lo.Map(results, func(item *VariantResult, _ int) *VariantView { return item.View() })
+
+		s.jobsMux.Lock()
+		job.Result = views[0].Result
+		if len(cfg.Variants) > 0 {
+			job.Variants = views
+		}
+		s.jobsMux.Unlock()
 
-		if err := s.sendResult(cfg.CallbackURL, completedResult); err != nil {
-			s.updateJobStatus(job.ID, StatusFailed, fmt.Errorf("failed to send final result: %w", err))
-			return
+		if err := saveJobReport(cfg, job, views); err != nil {
+			zap.S().Errorf("can not save report of simulation job %s: %v", job.ID, err)
 		}
 
 		s.updateJobStatus(job.ID, StatusCompleted, nil)
-	}()
+	}
+}
 
-	return job.ID, nil
+func saveJobReport(cfg *SimulatorConfig, job *SimulationJob, views []*VariantView) error {
+	if cfg.ReportPath == "" {
+		return nil
+	}
+
+	if len(cfg.Variants) > 0 {
+		return saveReport(cfg, comparisonPages(views), "comparison", "")
+	}
+
+	return saveReport(cfg, simulationPages(views[0].Result), job.RTP, job.Volatility)
 }
 
-func (s *SimulatorService) notifyGameSimulator(job *SimulationJob, rtp, volatility string) error {
-	result := job.toResult(rtp, volatility)
+func (s *SimulatorService) notifyGameSimulator(job *SimulationJob) error {
+	result := job.toResult()
 
 	maxRetries := 3
-	backoff := time.Second
+	backoff := s.callbackBackoff
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if err := s.sendResult(job.CallbackURL, result); err != nil {
@@ -241,58 +415,162 @@ func (s *SimulatorService) sendResult(url string, result *SimulatorResult) error
 	return nil
 }
 
-func (s *SimulatorService) GetJob(id string) (*SimulationJob, bool) {
+func (s *SimulatorService) GetJob(id string) (*SimulationJob, error) {
 	s.jobsMux.RLock()
-	defer s.jobsMux.RUnlock()
+	running, ok := s.running[id]
+	s.jobsMux.RUnlock()
+
+	if ok {
+		return s.snapshot(running.job), nil
+	}
+
+	return s.jobs.Get(id)
+}
+
+// ListJobs returns all jobs, the newest first.
+func (s *SimulatorService) ListJobs() ([]*SimulationJob, error) {
+	stored, err := s.jobs.List()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := lo.Map(stored, func(item *SimulationJob, _ int) *SimulationJob {
+		job, err := s.GetJob(item.ID)
+		if err != nil {
+			return item
+		}
+
+		return job
+	})
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+
+	return jobs, nil
+}
+
+// CancelJob stops the pending or running job, the workers stop on the next spin.
+func (s *SimulatorService) CancelJob(id string) error {
+	s.jobsMux.RLock()
+	running, ok := s.running[id]
+	s.jobsMux.RUnlock()
+
+	if ok {
+		running.cancel()
 
-	job, exists := s.jobs[id]
-	return job, exists
+		return nil
+	}
+
+	if _, err := s.jobs.Get(id); err != nil {
+		return err
+	}
+
+	return ErrJobNotRunning
 }
 
-func (s *SimulatorService) createJob(config *SimulatorConfig) *SimulationJob {
+func (s *SimulatorService) createJob(config *SimulatorConfig, rtp, volatility string) (*SimulationJob, context.Context, error) {
 	job := &SimulationJob{
 		ID:          uuid.New().String(),
+		Game:        config.GameName,
+		RTP:         rtp,
+		Volatility:  volatility,
 		Status:      StatusPending,
 		CallbackURL: config.CallbackURL,
+		Instance:    s.instance,
+		Spins:       config.Spins * int64(len(variants(config, rtp, volatility))),
 		CreatedAt:   time.Now(),
 	}
 
+	if err := s.jobs.Save(job); err != nil {
+		return nil, nil, fmt.Errorf("failed to save job: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
 	s.jobsMux.Lock()
-	s.jobs[job.ID] = job
+	s.running[job.ID] = &runningJob{job: job, cancel: cancel}
 	s.jobsMux.Unlock()
 
-	return job
+	return job, ctx, nil
 }
 
-func (s *SimulatorService) updateJobStatus(jobID string, status SimulationStatus, err error) {
+func (s *SimulatorService) finishJob(jobID string) {
 	s.jobsMux.Lock()
 	defer s.jobsMux.Unlock()
 
-	if job, exists := s.jobs[jobID]; exists {
+	if running, ok := s.running[jobID]; ok {
+		running.cancel()
+		delete(s.running, jobID)
+	}
+}
+
+func (s *SimulatorService) snapshot(job *SimulationJob) *SimulationJob {
+	s.jobsMux.RLock()
+	defer s.jobsMux.RUnlock()
+
+	copied := *job
+
+	return &copied
+}
+
+func (s *SimulatorService) updateJobStatus(jobID string, status SimulationStatus, err error) {
+	s.updateJob(jobID, func(job *SimulationJob) {
 		job.Status = status
 		if err != nil {
 			job.Error = err.Error()
 		}
-		if status == StatusCompleted || status == StatusFailed {
+		if status.Final() {
 			now := time.Now()
 			job.CompletedAt = &now
 		}
+	})
+}
+
+// updateJob changes the running job under the lock and saves it.
+func (s *SimulatorService) updateJob(jobID string, update func(job *SimulationJob)) {
+	s.jobsMux.Lock()
+
+	running, exists := s.running[jobID]
+	if !exists {
+		s.jobsMux.Unlock()
+
+		return
+	}
+
+	update(running.job)
+
+	copied := *running.job
+	s.jobsMux.Unlock()
+
+	if err := s.jobs.Save(&copied); err != nil {
+		zap.S().Errorf("can not save simulation job %s: %v", jobID, err)
 	}
 }
 
 func saveReport(cfg *SimulatorConfig, reportPages []utils.Page, rtp, volatility string) error {
-	excel, err := utils.ExportMultiPageXLSX(reportPages)
+	writer, err := utils.NewReportWriter(cfg.ReportFormat)
 	if err != nil {
 		return err
 	}
 
-	abs, err := filepath.Abs(cfg.ReportPath)
+	base, err := reportBase(cfg, rtp, volatility)
 	if err != nil {
 		return err
 	}
 
+	return writer.Write(base, reportPages)
+}
+
+// reportBase returns the report path without the extension, the report directory is created if needed.
+func reportBase(cfg *SimulatorConfig, parts ...string) (string, error) {
+	abs, err := filepath.Abs(cfg.ReportPath)
+	if err != nil {
+		return "", err
+	}
+
 	if err := os.MkdirAll(abs, os.ModePerm); err != nil {
-		return err
+		return "", err
 	}
 
 	withDash := func(str string) string {
@@ -300,24 +578,33 @@ func saveReport(cfg *SimulatorConfig, reportPages []utils.Page, rtp, volatility
 	}
 
 	filename := cfg.GameName
-	filename += withDash(rtp)
-	filename += withDash(volatility)
+	for _, part := range parts {
+		filename += withDash(part)
+	}
 	filename += withDash(time.Now().UTC().Format("2006-01-02-15-04-05"))
-	filename += ".xlsx"
 
-	file, err := os.Create(filepath.Join(abs, filename))
-	if err != nil {
-		return err
+	return filepath.Join(abs, filename), nil
+}
+
+// newSpinDump returns the target of the spin dump or nil if the dump is off.
+func newSpinDump(cfg *SimulatorConfig, parts ...string) (*spinDump, error) {
+	if cfg.SpinDump == "" {
+		return nil, nil
 	}
 
-	if err = excel.Write(file); err != nil {
-		return err
+	if cfg.SpinDump != CSVSpinDump && cfg.SpinDump != ColumnarSpinDump {
+		return nil, fmt.Errorf("unknown spin dump format %s", cfg.SpinDump)
 	}
 
-	return nil
+	base, err := reportBase(cfg, parts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spinDump{Format: cfg.SpinDump, Base: base + "-spins"}, nil
 }
 
-func (s *SimulatorService) Simulate(game string, count int64, wager int64, workersCount int) (*SimulationResult, error) {
+func (s *SimulatorService) Simulate(ctx context.Context, game string, count int64, wager int64, workersCount int) (*SimulationResult, error) {
 	res := &SimulationResult{
 		Wager: wager,
 		Count: count,
@@ -335,13 +622,49 @@ func (s *SimulatorService) Simulate(game string, count int64, wager int64, worke
 		BaseAwardStandardDeviation:  new(big.Float),
 		BonusAwardStandardDeviation: new(big.Float),
 		AwardStandardDeviation:      new(big.Float),
+
+		Features:  map[string]*FeatureResult{},
+		Choices:   map[string]*FeatureResult{},
+		Breakdown: newBreakdown(),
 	}
 
+	if s.seed != nil {
+		if _, ok := s.boot.SpinFactory.(engine.SeedableSpinFactory); !ok {
+			return nil, fmt.Errorf("spin factory %T does not implement engine.SeedableSpinFactory", s.boot.SpinFactory)
+		}
+
+		// seeded spins are played without the previous spin, so the chain of the game would be broken
+		if s.boot.ChainDependency {
+			return nil, errors.New("seeded simulation is not supported for the games with chain dependency")
+		}
+
+		res.Seed = s.seed
+	}
+
+	purchase, err := s.boot.Price(wager, s.generateParams, engine.PricingRules{
+		BuyBonus:         true,
+		DoubleChance:     true,
+		AllJurisdictions: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// returning on error cancels the workers too
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	type result struct {
+		Index          int64
+		OriginalWager  int64
 		Wager          int64
 		BaseAward      int64
 		BonusAward     int64
 		BonusTriggered bool
+		MaxWinReached  bool
+		Feature        string
+		Choice         string
+		Stats          []engine.Stat
 	}
 	now := time.Now()
 	bar := progressbar.NewOptions64(count,
@@ -360,7 +683,7 @@ func (s *SimulatorService) Simulate(game string, count int64, wager int64, worke
 
 		inputCh  = make(chan int64, workersCount)
 		outputCh = make(chan result, workersCount)
-		errCh    = make(chan error, 1)
+		errCh    = make(chan error, workersCount)
 
 		wg = new(sync.WaitGroup)
 	)
@@ -368,15 +691,30 @@ func (s *SimulatorService) Simulate(game string, count int64, wager int64, worke
 	worker := func(wg *sync.WaitGroup, inputCh <-chan int64, outputCh chan<- result) {
 		defer wg.Done()
 
-		var prevSpin engine.Spin
+		var (
+			prevSpin engine.Spin
+			seeded   *rng.SeededClient
+			factory  = factory
+		)
+
+		if s.seed != nil {
+			seeded = rng.NewSeededClient(*s.seed)
+			factory = factory.(engine.SeedableSpinFactory).WithRngClient(seeded)
+		}
 
 		for {
-			if _, ok := <-inputCh; !ok {
+			index, ok := <-inputCh
+			if !ok {
 				return
 			}
 
-			ctx := engine.Context{Context: context.Background()}
-			if prevSpin != nil {
+			ctx := engine.Context{Context: ctx, Purchase: purchase}
+
+			// seeded spins are independent: every spin has its own stream and no previous spin,
+			// so the results do not depend on the number of workers
+			if seeded != nil {
+				seeded.Seed(rng.DeriveSeed(*s.seed, uint64(index)))
+			} else if prevSpin != nil {
 				ctx.LastSpin = prevSpin
 			}
 
@@ -396,13 +734,50 @@ func (s *SimulatorService) Simulate(game string, count int64, wager int64, worke
 				}
 			}
 
+			spin, choice, err := s.applyChoices(ctx, spin, factory, index)
+			if err != nil {
+				errCh <- err
+
+				return
+			}
+
 			prevSpin = spin
 
-			outputCh <- result{
+			if err := engine.CheckPurchase(spin, purchase); err != nil {
+				errCh <- err
+
+				return
+			}
+
+			baseAward, bonusAward, maxWinReached := engine.ApplyMaxWin(spin, s.boot.MaxWin(wager))
+
+			var stats []engine.Stat
+			if statsSpin, ok := spin.(engine.SimulationStats); ok {
+				stats = statsSpin.SimulationStats()
+			}
+
+			feature := engine.BaseFeature
+			if featureSpin, ok := spin.(engine.FeatureSpin); ok {
+				feature = featureSpin.Feature()
+			}
+
+			output := result{
+				Index:          index,
+				OriginalWager:  spin.OriginalWager(),
 				Wager:          spin.Wager(),
-				BaseAward:      spin.BaseAward(),
-				BonusAward:     spin.BonusAward(),
+				BaseAward:      baseAward,
+				BonusAward:     bonusAward,
 				BonusTriggered: spin.BonusTriggered(),
+				MaxWinReached:  maxWinReached,
+				Feature:        feature,
+				Choice:         choice,
+				Stats:          stats,
+			}
+
+			select {
+			case outputCh <- output:
+			case <-ctx.Done():
+				return
 			}
 		}
 	}
@@ -411,7 +786,11 @@ func (s *SimulatorService) Simulate(game string, count int64, wager int64, worke
 		defer close(inputCh)
 
 		for i := int64(0); i < count; i++ {
-			inputCh <- i
+			select {
+			case inputCh <- i:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 
@@ -425,7 +804,109 @@ func (s *SimulatorService) Simulate(game string, count int64, wager int64, worke
 		close(outputCh)
 	}()
 
+	var dump SpinDumpWriter
+
+	if s.dump != nil {
+		if dump, err = s.dump.open(); err != nil {
+			return nil, fmt.Errorf("can not open spin dump: %w", err)
+		}
+
+		// the dump is closed explicitly on success, so its errors are returned
+		defer func() {
+			if dump != nil {
+				dump.Close()
+			}
+		}()
+	}
+
 	i := 0
+	step := checkpointStep(count, s.checkpointEvery)
+	progressStep := max(count/100, 1)
+
+	aggregate := func(output result) error {
+		award := output.BaseAward + output.BonusAward
+
+		res.BaseAward.Add(res.BaseAward, big.NewInt(output.BaseAward))
+		res.BonusAward.Add(res.BonusAward, big.NewInt(output.BonusAward))
+		res.Award.Add(res.Award, big.NewInt(award))
+		res.Spent.Add(res.Spent, big.NewInt(output.Wager))
+
+		if award > res.MaxExposure {
+			res.MaxExposure = award
+		}
+
+		if award > 0 {
+			res.BaseAwardCount++
+		}
+
+		if output.BonusTriggered {
+			res.BonusGameCount++
+		}
+
+		if output.MaxWinReached {
+			res.MaxWinCount++
+		}
+
+		feature, ok := res.Features[output.Feature]
+		if !ok {
+			feature = &FeatureResult{Feature: output.Feature, Spent: new(big.Int), Award: new(big.Int)}
+			res.Features[output.Feature] = feature
+		}
+
+		feature.Count++
+		feature.Spent.Add(feature.Spent, big.NewInt(output.Wager))
+		feature.Award.Add(feature.Award, big.NewInt(award))
+
+		res.Breakdown.add(output.Stats, award, wager)
+		res.addChoice(output.Choice, output.Wager, award)
+
+		if award >= wager*1 {
+			res.X1Count++
+		}
+
+		if award >= wager*10 {
+			res.X10Count++
+		}
+
+		if award >= wager*100 {
+			res.X100Count++
+		}
+
+		res.BaseAwardSquareSum.Add(res.BaseAwardSquareSum, big.NewInt(0).Mul(big.NewInt(output.BaseAward), big.NewInt(output.BaseAward)))
+		res.BonusAwardSquareSum.Add(res.BonusAwardSquareSum, big.NewInt(0).Mul(big.NewInt(output.BonusAward), big.NewInt(output.BonusAward)))
+		res.AwardSquareSum.Add(res.AwardSquareSum, big.NewInt(0).Mul(big.NewInt(award), big.NewInt(award)))
+
+		_ = bar.Add(1) // ignore error
+
+		i++
+
+		if s.onProgress != nil && (int64(i)%progressStep == 0 || int64(i) == count) {
+			s.onProgress(int64(i))
+		}
+
+		if int64(i)%step == 0 {
+			rtp, _ := new(big.Float).Quo(new(big.Float).SetInt(res.Award), new(big.Float).SetInt(res.Spent)).Float64()
+			res.Convergence = append(res.Convergence, Checkpoint{Spins: int64(i), RTP: rtp})
+		}
+
+		if dump == nil {
+			return nil
+		}
+
+		return dump.Write(SpinRecord{
+			Index:          output.Index,
+			OriginalWager:  output.OriginalWager,
+			Wager:          output.Wager,
+			BaseAward:      output.BaseAward,
+			BonusAward:     output.BonusAward,
+			BonusTriggered: output.BonusTriggered,
+			MaxWinReached:  output.MaxWinReached,
+			Feature:        output.Feature,
+		})
+	}
+
+	// results are aggregated in the order of spins, so the convergence series does not depend on the workers
+	pending := map[int64]result{}
 
 Loop:
 	for {
@@ -435,46 +916,48 @@ Loop:
 				break Loop
 			}
 
-			award := output.BaseAward + output.BonusAward
+			pending[output.Index] = output
 
-			res.BaseAward.Add(res.BaseAward, big.NewInt(output.BaseAward))
-			res.BonusAward.Add(res.BonusAward, big.NewInt(output.BonusAward))
-			res.Award.Add(res.Award, big.NewInt(award))
-			res.Spent.Add(res.Spent, big.NewInt(output.Wager))
-
-			if award > res.MaxExposure {
-				res.MaxExposure = award
-			}
-
-			if award > 0 {
-				res.BaseAwardCount++
-			}
+			for {
+				next, ok := pending[int64(i)]
+				if !ok {
+					break
+				}
 
-			if output.BonusTriggered {
-				res.BonusGameCount++
-			}
+				delete(pending, int64(i))
 
-			if award >= wager*1 {
-				res.X1Count++
+				if err := aggregate(next); err != nil {
+					return nil, fmt.Errorf("can not write spin dump: %w", err)
+				}
 			}
+		case err := <-errCh:
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
 
-			if award >= wager*10 {
-				res.X10Count++
-			}
+	// the output channel is closed after all the workers are stopped, so the error of a failed worker is buffered
+	select {
+	case err := <-errCh:
+		return nil, err
+	default:
+	}
 
-			if award >= wager*100 {
-				res.X100Count++
-			}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-			res.BaseAwardSquareSum.Add(res.BaseAwardSquareSum, big.NewInt(0).Mul(big.NewInt(output.BaseAward), big.NewInt(output.BaseAward)))
-			res.BonusAwardSquareSum.Add(res.BonusAwardSquareSum, big.NewInt(0).Mul(big.NewInt(output.BonusAward), big.NewInt(output.BonusAward)))
-			res.AwardSquareSum.Add(res.AwardSquareSum, big.NewInt(0).Mul(big.NewInt(award), big.NewInt(award)))
+	if int64(i) != count {
+		return nil, fmt.Errorf("simulation stopped after %d of %d spins", i, count)
+	}
 
-			_ = bar.Add(1) // ignore error
+	if dump != nil {
+		err := dump.Close()
+		dump = nil
 
-			i++
-		case err := <-errCh:
-			return nil, err
+		if err != nil {
+			return nil, fmt.Errorf("can not close spin dump: %w", err)
 		}
 	}
 
@@ -501,11 +984,16 @@ Loop:
 	res.RTPBaseGame, _ = new(big.Float).Quo(baseF, spentF).Float64()
 	res.RTPBonusGame, _ = new(big.Float).Quo(bonusF, spentF).Float64()
 
+	res.ConfidenceIntervals = confidenceIntervals(res)
+	res.TargetPrecision = s.targetPrecision
+	res.SpinsForPrecision = spinsForPrecision(res, s.targetPrecision)
+
 	return res, nil
 }
 
 type SimulationResult struct {
 	Game        string   `xlsx:"Game"`
+	Seed        *uint64  `xlsx:"-"`
 	Count       int64    `xlsx:"Count"`
 	Wager       int64    `xlsx:"Wager"`
 	Spent       *big.Int `xlsx:"Spent"`
@@ -518,6 +1006,8 @@ type SimulationResult struct {
 	X10Count  int64 `xlsx:"X10 Count"`
 	X100Count int64 `xlsx:"X100 Count"`
 
+	MaxWinCount int64 `xlsx:"Max Win Count"`
+
 	BaseAward  *big.Int `xlsx:"Base BaseAward"`
 	BonusAward *big.Int `xlsx:"Bonus BaseAward"`
 	Award      *big.Int `xlsx:"Award"`
@@ -535,11 +1025,51 @@ type SimulationResult struct {
 	RTP          float64 `xlsx:"RTP"`
 	RTPBaseGame  float64 `xlsx:"RTP Base Game"`
 	RTPBonusGame float64 `xlsx:"RTP Bonus Game"`
+
+	Features  map[string]*FeatureResult `xlsx:"-"` // feature -> result
+	Choices   map[string]*FeatureResult `xlsx:"-"` // made choices -> result
+	Breakdown *Breakdown                `xlsx:"-"`
+
+	ConfidenceIntervals []ConfidenceInterval `xlsx:"-"`
+	Convergence         []Checkpoint         `xlsx:"-"`
+	TargetPrecision     float64              `xlsx:"-"`
+	SpinsForPrecision   int64                `xlsx:"-"`
+}
+
+// FeatureResult is the result of spins played with the purchased feature (engine.FeatureSpin).
+type FeatureResult struct {
+	Feature string
+	Count   int64
+	Spent   *big.Int
+	Award   *big.Int
+}
+
+func (r *FeatureResult) View(total int64) *FeatureView {
+	rtp, _ := new(big.Float).Quo(new(big.Float).SetInt(r.Award), new(big.Float).SetInt(r.Spent)).Float64()
+
+	return &FeatureView{
+		Feature: r.Feature,
+		Count:   fmt.Sprint(r.Count),
+		Rate:    countToRate(r.Count, total),
+		Spent:   r.Spent.String(),
+		Award:   r.Award.String(),
+		RTP:     floatWithPrecision(rtp),
+	}
+}
+
+type FeatureView struct {
+	Feature string `json:"feature" xlsx:"Feature"`
+	Count   string `json:"count" xlsx:"Count"`
+	Rate    string `json:"rate" xlsx:"Rate"`
+	Spent   string `json:"spent" xlsx:"Spent"`
+	Award   string `json:"award" xlsx:"Award"`
+	RTP     string `json:"rtp" xlsx:"RTP"`
 }
 
 func (r SimulationResult) View() *SimulationView {
 	return &SimulationView{
 		Game:        r.Game,
+		Seed:        lo.TernaryF(r.Seed != nil, func() string { return fmt.Sprint(*r.Seed) }, func() string { return "-" }),
 		Count:       fmt.Sprint(r.Count),
 		Wager:       fmt.Sprint(r.Wager),
 		Spent:       fmt.Sprint(r.Spent),
@@ -559,6 +1089,9 @@ func (r SimulationResult) View() *SimulationView {
 		X10Rate:  countToRate(r.X10Count, r.Count),
 		X100Rate: countToRate(r.X100Count, r.Count),
 
+		MaxWinCount: fmt.Sprint(r.MaxWinCount),
+		MaxWinRate:  countToRate(r.MaxWinCount, r.Count),
+
 		BaseAward:  r.BaseAward.String(),
 		BonusAward: r.BonusAward.String(),
 		Award:      r.Award.String(),
@@ -576,11 +1109,33 @@ func (r SimulationResult) View() *SimulationView {
 		RTP:          floatWithPrecision(r.RTP),
 		RTPBaseGame:  floatWithPrecision(r.RTPBaseGame),
 		RTPBonusGame: floatWithPrecision(r.RTPBonusGame),
+
+		Features: r.featureViews(),
+		Choices:  r.choiceViews(),
+
+		Symbols:   r.Breakdown.symbolViews(r.Count, r.Spent),
+		Triggers:  r.Breakdown.triggerViews(r.Count),
+		Histogram: r.Breakdown.histogramViews(r.Count),
+
+		ConfidenceIntervals: r.confidenceViews(),
+		Convergence:         r.convergenceViews(),
+		TargetPrecision:     floatWithPrecision(r.TargetPrecision),
+		SpinsForPrecision:   fmt.Sprint(r.SpinsForPrecision),
 	}
 }
 
+func (r SimulationResult) featureViews() []*FeatureView {
+	features := lo.Keys(r.Features)
+	sort.Strings(features)
+
+	return lo.Map(features, func(item string, _ int) *FeatureView {
+		return r.Features[item].View(r.Count)
+	})
+}
+
 type SimulationView struct {
 	Game        string `json:"game" xlsx:"Game"`
+	Seed        string `json:"seed" xlsx:"Seed"`
 	Count       string `json:"count" xlsx:"Count"`
 	Wager       string `json:"wager" xlsx:"Wager"`
 	Spent       string `json:"spent" xlsx:"Spent"`
@@ -610,6 +1165,11 @@ type SimulationView struct {
 
 	NewLine5 string `xlsx:""`
 
+	MaxWinCount string `json:"max_win_count" xlsx:"Max Win Count"`
+	MaxWinRate  string `json:"max_win_rate" xlsx:"Max Win Rate"`
+
+	NewLine10 string `xlsx:""`
+
 	BaseAward  string `json:"base_award" xlsx:"Base BaseAward"`
 	BonusAward string `json:"bonus_award" xlsx:"Bonus BaseAward"`
 	Award      string `json:"award" xlsx:"BaseAward"`
@@ -635,6 +1195,20 @@ type SimulationView struct {
 	RTP          string `json:"rtp" xlsx:"RTP"`
 	RTPBaseGame  string `json:"rtp_base_game" xlsx:"RTP Base Game"`
 	RTPBonusGame string `json:"rtp_bonus_game" xlsx:"RTP Bonus Game"`
+
+	NewLine11 string `xlsx:""`
+
+	TargetPrecision   string `json:"target_precision" xlsx:"Target Precision (95%)"`
+	SpinsForPrecision string `json:"spins_for_precision" xlsx:"Spins For Target Precision"`
+
+	ConfidenceIntervals []*ConfidenceIntervalView `json:"confidence_intervals" xlsx:"-"`
+	Convergence         []*CheckpointView         `json:"convergence" xlsx:"-"`
+
+	Features  []*FeatureView   `json:"features" xlsx:"-"`
+	Choices   []*ChoiceView    `json:"choices" xlsx:"-"`
+	Symbols   []*SymbolView    `json:"symbols" xlsx:"-"`
+	Triggers  []*TriggerView   `json:"triggers" xlsx:"-"`
+	Histogram []*HistogramView `json:"histogram" xlsx:"-"`
 }
 
 func countToRate(count, total int64) string {
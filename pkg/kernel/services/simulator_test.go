@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSimulator(boot *engine.Bootstrap) *SimulatorService {
+	return &SimulatorService{
+		boot:            boot,
+		jobs:            NewMemoryJobStore(),
+		running:         map[string]*runningJob{},
+		client:          http.DefaultClient,
+		callbackBackoff: time.Millisecond,
+	}
+}
+
+func TestSimulatorService_Simulate_SeedIndependentOfWorkers(t *testing.T) {
+	seed := uint64(42)
+
+	simulate := func(workers int) *SimulationView {
+		s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 10)})
+		s.seed = &seed
+
+		res, err := s.Simulate(context.Background(), "test", 1000, 100, workers)
+		require.NoError(t, err)
+
+		return res.View()
+	}
+
+	single := simulate(1)
+
+	require.Equal(t, "1000", single.Count)
+	require.Equal(t, single, simulate(4))
+	require.Equal(t, single, simulate(16))
+}
+
+func TestSimulatorService_Simulate_SeedChainDependency(t *testing.T) {
+	seed := uint64(42)
+
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 10), ChainDependency: true})
+	s.seed = &seed
+
+	_, err := s.Simulate(context.Background(), "test", 10, 100, 1)
+	require.Error(t, err)
+}
+
+func TestSimulatorService_Simulate_WorkerError(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		factory := newTestFactory(1, 10)
+		factory.failAt = 500
+
+		s := newTestSimulator(&engine.Bootstrap{SpinFactory: factory})
+
+		// the spins after the failed one are played by other workers, the result must not be truncated
+		_, err := s.Simulate(context.Background(), "test", 1000, 100, workers)
+		require.ErrorIs(t, err, errTestGenerate)
+	}
+}
+
+func TestSimulatorService_Simulate_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := newTestSimulator(&engine.Bootstrap{SpinFactory: newTestFactory(1, 10)})
+
+	_, err := s.Simulate(ctx, "test", 1000, 100, 2)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -24,6 +24,7 @@ func (h *gameFlowHandler) Register(router *gin.RouterGroup) {
 
 	core.POST("state", h.initState)
 	core.POST("wager", h.wager)
+	core.POST("autoplay", h.autoplay)
 	core.POST("gamble_any_win", h.gambleAnyWin)
 	core.POST("keep_generating", h.keepGenerating)
 	core.GET("spins_history", h.history)
@@ -84,6 +85,34 @@ func (h *gameFlowHandler) wager(ctx *gin.Context) {
 	http.OK(ctx, gameState, nil)
 }
 
+// autoplay returns the played rounds in one batch, the client sends the next request to continue.
+func (h *gameFlowHandler) autoplay(ctx *gin.Context) {
+	payload, err := bindBody(ctx)
+	if err != nil {
+		zap.S().Error("autoplay: ", err)
+		http.BadRequest(ctx, err, nil)
+
+		return
+	}
+
+	md, err := getMetaData(ctx, payload)
+	if err != nil {
+		zap.S().Error("getMetaData: ", err)
+		handleServiceError(ctx, err)
+
+		return
+	}
+
+	resp, err := h.facade.Autoplay(ctx.Request.Context(), payload, md, nil)
+	if err != nil {
+		handleServiceError(ctx, err)
+
+		return
+	}
+
+	http.OK(ctx, resp, nil)
+}
+
 func (h *gameFlowHandler) gambleAnyWin(ctx *gin.Context) {
 	payload, err := bindBody(ctx)
 	if err != nil {
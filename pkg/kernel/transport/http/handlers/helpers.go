@@ -17,6 +17,21 @@ var errorMap = map[error]func(ctx *gin.Context, err error, meta map[string]inter
 	errs.ErrWrongFreeSpinID:       http.Conflict,
 	errs.ErrNotEnoughMoney:        http.PaymentRequired,
 	errs.ErrBalanceTooLow:         http.PaymentRequired,
+	errs.ErrIdempotencyKeyReused:  http.Conflict,
+	errs.ErrRequestInProgress:     http.Conflict,
+
+	errs.ErrCanNotGamble:        http.Conflict,
+	errs.ErrGambleLost:          http.Conflict,
+	errs.ErrGambleLimitReached:  http.Conflict,
+	errs.ErrGambleMaxWinReached: http.Conflict,
+	errs.ErrNoWinToGamble:       http.Conflict,
+
+	errs.ErrStakeLimitExceeded:      http.Forbidden,
+	errs.ErrSessionLossLimitReached: http.Forbidden,
+	errs.ErrSpinTooFast:             http.Forbidden,
+	errs.ErrAutoplayNotAllowed:      http.Forbidden,
+	errs.ErrAutoplayIsNotSupported:  http.Forbidden,
+	errs.ErrTurboNotAllowed:         http.Forbidden,
 
 	errs.ErrUserIsBlocked:             http.Forbidden,
 	errs.ErrUserHasDifferentCurrency:  http.Conflict,
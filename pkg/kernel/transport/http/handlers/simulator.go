@@ -1,22 +1,21 @@
 package handlers
 
 import (
-	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/constants"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/engine"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/errs"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/services"
 	httpPackage "bitbucket.org/play-workspace/base-slot-server/pkg/kernel/transport/http"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/validator"
-	"bitbucket.org/play-workspace/base-slot-server/pkg/rng"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
 	"github.com/sarulabs/di"
 	"go.uber.org/zap"
-	"gopkg.in/yaml.v2"
+	"io"
 	"net/url"
-	"os"
-	"os/exec"
 	"reflect"
+	"time"
 )
 
 type Request struct {
@@ -26,9 +25,26 @@ type Request struct {
 	Wager          int64                  `json:"wager" validate:"required"`
 	Workers        int                    `json:"workers" validate:"required"`
 	GenerateParams map[string]interface{} `json:"generateParams"`
+	RTP            string                 `json:"rtp" validate:"required_without=Variants"`
+	Volatility     string                 `json:"volatility" validate:"required_without=Variants"`
+	CallbackURL    string                 `json:"callbackURL" validate:"required"`
+
+	// Variants are simulated concurrently and compared in one report, RTP and Volatility are ignored with them.
+	Variants []VariantRequest `json:"variants" validate:"omitempty,dive"`
+
+	CheckpointEvery int64   `json:"checkpointEvery"`
+	TargetPrecision float64 `json:"targetPrecision"`
+	Seed            *uint64 `json:"seed"`
+	ReportFormat    string  `json:"reportFormat" validate:"omitempty,oneof=xlsx csv json"`
+	SpinDump        string  `json:"spinDump" validate:"omitempty,oneof=csv columnar"`
+	ChoicePolicy    string  `json:"choicePolicy" validate:"omitempty,oneof=random fixed all"`
+	ChoiceIndex     int     `json:"choiceIndex" validate:"min=0"`
+}
+
+type VariantRequest struct {
 	RTP            string                 `json:"rtp" validate:"required"`
 	Volatility     string                 `json:"volatility" validate:"required"`
-	CallbackURL    string                 `json:"callbackURL" validate:"required"`
+	GenerateParams map[string]interface{} `json:"generateParams"` // empty means the generate params of the request
 }
 
 var gamesWithBonusChoice = []string{
@@ -37,11 +53,7 @@ var gamesWithBonusChoice = []string{
 	"coral-reef-flexiways",
 }
 
-const (
-	configPath   = "/app/config.yml"
-	appPath      = "/app/app"
-	tempFilePath = "/app/temp.yml"
-)
+const progressInterval = time.Second
 
 type simulatorHandler struct {
 	ctn              di.Container
@@ -50,8 +62,7 @@ type simulatorHandler struct {
 }
 
 func NewSimulatorHandler(ctn di.Container, validationEngine *validator.Validator, service *services.SimulatorService) httpPackage.Handler {
-	rand := ctn.Get(constants.RNGName).(rng.Client)
-	bonusChoiceWrapper := createBonusChoiceWrapper(rand)
+	bonusChoiceWrapper := createBonusChoiceWrapper()
 
 	for _, game := range gamesWithBonusChoice {
 		service.RegisterGameWrapper(game, bonusChoiceWrapper)
@@ -64,7 +75,9 @@ func NewSimulatorHandler(ctn di.Container, validationEngine *validator.Validator
 	}
 }
 
-func createBonusChoiceWrapper(rand rng.Client) services.KeepGenerateWrapper {
+// createBonusChoiceWrapper picks a random bonus choice with the rng of the factory, so seeded simulations are reproducible.
+// It is kept for the games with the BonusChoice field, new games implement engine.ChoiceSpin instead.
+func createBonusChoiceWrapper() services.KeepGenerateWrapper {
 	return func(ctx engine.Context, spin engine.Spin, factory engine.SpinFactory) (engine.Spin, error) {
 		sp, ok := spin.(interface{})
 		if !ok {
@@ -79,7 +92,7 @@ func createBonusChoiceWrapper(rand rng.Client) services.KeepGenerateWrapper {
 			if bonusChoiceField.IsValid() && !bonusChoiceField.IsNil() && bonusChoiceField.Len() > 0 {
 				ctx.LastSpin = spin
 
-				index, err := rand.Rand(uint64(bonusChoiceField.Len()))
+				index, err := factory.GetRngClient().Rand(uint64(bonusChoiceField.Len()))
 				if err != nil {
 					return nil, err
 				}
@@ -105,7 +118,10 @@ func (s *simulatorHandler) Shutdown() {}
 
 func (s *simulatorHandler) Register(router *gin.RouterGroup) {
 	router.POST("simulator", s.RunSimulate)
+	router.GET("simulator/jobs", s.ListSimulations)
 	router.GET("simulator/:jobId/status", s.GetSimulationStatus)
+	router.GET("simulator/:jobId/progress", s.StreamSimulationProgress)
+	router.POST("simulator/:jobId/cancel", s.CancelSimulation)
 }
 
 func (s *simulatorHandler) RunSimulate(ctx *gin.Context) {
@@ -123,6 +139,23 @@ func (s *simulatorHandler) RunSimulate(ctx *gin.Context) {
 		Workers:        req.Workers,
 		GenerateParams: req.GenerateParams,
 		CallbackURL:    req.CallbackURL,
+
+		CheckpointEvery: req.CheckpointEvery,
+		TargetPrecision: req.TargetPrecision,
+		Seed:            req.Seed,
+		ReportFormat:    req.ReportFormat,
+		SpinDump:        req.SpinDump,
+		ChoicePolicy:    req.ChoicePolicy,
+		ChoiceIndex:     req.ChoiceIndex,
+
+		Variants: lo.Map(req.Variants, func(item VariantRequest, _ int) services.SimulationVariant {
+			variant := services.SimulationVariant{RTP: item.RTP, Volatility: item.Volatility}
+			if len(item.GenerateParams) > 0 {
+				variant.GenerateParams = item.GenerateParams
+			}
+
+			return variant
+		}),
 	}
 
 	jobID, err := s.simulatorService.CreateSimulation(cfg, req.RTP, req.Volatility)
@@ -142,136 +175,126 @@ func (s *simulatorHandler) RunSimulate(ctx *gin.Context) {
 }
 
 func (s *simulatorHandler) GetSimulationStatus(ctx *gin.Context) {
-	jobID := ctx.Param("jobId")
-
-	job, exists := s.simulatorService.GetJob(jobID)
-	if !exists {
-		httpPackage.NotFound(ctx, fmt.Errorf("job not found"), nil)
+	job, ok := s.getJob(ctx)
+	if !ok {
 		return
 	}
 
-	response := map[string]interface{}{
-		"job_id":    job.ID,
-		"status":    string(job.Status),
-		"game_name": job.Result.Game,
-	}
+	httpPackage.OK(ctx, "Job status retrieved", jobResponse(job))
+}
 
-	if job.Error != "" {
-		response["error"] = job.Error
+func (s *simulatorHandler) ListSimulations(ctx *gin.Context) {
+	jobs, err := s.simulatorService.ListJobs()
+	if err != nil {
+		httpPackage.ServerError(ctx, fmt.Errorf("failed to list jobs: %w", err), nil)
+		return
 	}
 
-	httpPackage.OK(ctx, "Job status retrieved", response)
+	httpPackage.OK(ctx, "Jobs retrieved", map[string]interface{}{
+		"jobs": lo.Map(jobs, func(item *services.SimulationJob, _ int) map[string]interface{} {
+			return jobResponse(item)
+		}),
+	})
 }
 
-func (s *simulatorHandler) parseAndValidateRequests(ctx *gin.Context) (*Request, error) {
-	var req Request
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		return nil, errs.NewInternalValidationError(err)
-	}
+func (s *simulatorHandler) CancelSimulation(ctx *gin.Context) {
+	err := s.simulatorService.CancelJob(ctx.Param("jobId"))
 
-	if err := s.validationEngine.ValidateStruct(&req); err != nil {
-		return nil, errs.NewInternalValidationError(err)
-	}
-
-	if _, err := url.Parse(req.CallbackURL); err != nil {
-		return nil, errs.NewInternalValidationError(fmt.Errorf("invalid callback URL: %w", err))
+	switch {
+	case errors.Is(err, services.ErrJobNotFound):
+		httpPackage.NotFound(ctx, err, nil)
+	case errors.Is(err, services.ErrJobNotRunning):
+		httpPackage.BadRequest(ctx, err, nil)
+	case err != nil:
+		httpPackage.ServerError(ctx, err, nil)
+	default:
+		httpPackage.OK(ctx, "Job cancellation requested", map[string]interface{}{"job_id": ctx.Param("jobId")})
 	}
-
-	return &req, nil
 }
 
-func (s *simulatorHandler) updateConfigWithRequest(originConfig map[string]interface{}, req *Request) (string, error) {
-	generateParams := make(map[string]interface{})
-	for key, value := range req.GenerateParams {
-		generateParams[key] = value
-	}
-	originConfig["simulator"] = map[string]interface{}{
-		"gameName":       req.GameName,
-		"reportPath":     req.ReportPath,
-		"spins":          req.Spins,
-		"wager":          req.Wager,
-		"workers":        req.Workers,
-		"GenerateParams": generateParams,
+// StreamSimulationProgress sends the job as server-sent events every second until it is finished.
+func (s *simulatorHandler) StreamSimulationProgress(ctx *gin.Context) {
+	if _, ok := s.getJob(ctx); !ok {
+		return
 	}
 
-	originConfig["engine"] = map[string]interface{}{
-		"rtp":               req.RTP,
-		"volatility":        req.Volatility,
-		"buildVersion":      1,
-		"isCheatsAvailable": true,
-		"mockRng":           false,
-	}
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
 
-	configBytes, err := yaml.Marshal(originConfig)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal config: %w", err)
-	}
+	ctx.Stream(func(w io.Writer) bool {
+		job, err := s.simulatorService.GetJob(ctx.Param("jobId"))
+		if err != nil {
+			ctx.SSEvent("error", err.Error())
 
-	return string(configBytes), nil
-}
+			return false
+		}
 
-func createTempConfigFile(configYaml string) (string, error) {
-	zap.S().Info("Starting to create temp config file")
+		ctx.SSEvent("progress", jobResponse(job))
 
-	tempFile, err := os.Create(tempFilePath)
-	if err != nil {
-		zap.S().Error("Error when creating a temporary file", "error", err)
-		return "", fmt.Errorf("could not create temp file: %w", err)
-	}
+		if job.Status.Final() {
+			return false
+		}
 
-	zap.S().Info("Temporary file created", "file", tempFile.Name())
+		select {
+		case <-ticker.C:
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
 
-	if _, err := tempFile.Write([]byte(configYaml)); err != nil {
-		zap.S().Error("Error when writing to a file", "error", err)
-		return "", fmt.Errorf("could not write to temp file: %w", err)
-	}
+func (s *simulatorHandler) getJob(ctx *gin.Context) (*services.SimulationJob, bool) {
+	job, err := s.simulatorService.GetJob(ctx.Param("jobId"))
+
+	switch {
+	case errors.Is(err, services.ErrJobNotFound):
+		httpPackage.NotFound(ctx, fmt.Errorf("job not found"), nil)
 
-	zap.S().Info("Successfully wrote to temp file")
+		return nil, false
+	case err != nil:
+		httpPackage.ServerError(ctx, err, nil)
 
-	if err := tempFile.Close(); err != nil {
-		zap.S().Error("Error when closing a file", "error", err)
-		return "", fmt.Errorf("could not close temp file: %w", err)
+		return nil, false
 	}
 
-	zap.S().Info("Temporary config file created and closed", "file", tempFile.Name())
-	return tempFile.Name(), nil
+	return job, true
 }
 
-func (s *simulatorHandler) runSingleSimulation(req *Request) error {
-	originConfig := loadConfig(configPath)
-
-	updatedConfig, err := s.updateConfigWithRequest(originConfig, req)
-	if err != nil {
-		zap.S().Error("simulator: failed to update config", err)
-		return err
+func jobResponse(job *services.SimulationJob) map[string]interface{} {
+	response := map[string]interface{}{
+		"job_id":     job.ID,
+		"status":     string(job.Status),
+		"game_name":  job.Game,
+		"spins":      job.Spins,
+		"done":       job.Done,
+		"created_at": job.CreatedAt,
 	}
 
-	tempFileName, err := createTempConfigFile(updatedConfig)
-	if err != nil {
-		zap.S().Error("Failed to create temporary config file", "error", err)
-		return err
+	if job.Error != "" {
+		response["error"] = job.Error
 	}
-	defer os.Remove(tempFileName)
 
-	cmd := exec.Command(appPath, fmt.Sprintf("--config=%s", tempFileName))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if job.CompletedAt != nil {
+		response["completed_at"] = job.CompletedAt
+	}
 
-	return cmd.Run()
+	return response
 }
 
-func loadConfig(path string) map[string]interface{} {
-	config := make(map[string]interface{})
+func (s *simulatorHandler) parseAndValidateRequests(ctx *gin.Context) (*Request, error) {
+	var req Request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, errs.NewInternalValidationError(err)
+	}
 
-	yamlFile, err := os.ReadFile(path)
-	if err != nil {
-		panic(err)
+	if err := s.validationEngine.ValidateStruct(&req); err != nil {
+		return nil, errs.NewInternalValidationError(err)
 	}
 
-	err = yaml.Unmarshal(yamlFile, &config)
-	if err != nil {
-		panic(err)
+	if _, err := url.Parse(req.CallbackURL); err != nil {
+		return nil, errs.NewInternalValidationError(fmt.Errorf("invalid callback URL: %w", err))
 	}
 
-	return config
+	return &req, nil
 }
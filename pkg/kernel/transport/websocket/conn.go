@@ -29,6 +29,10 @@ type Connection struct {
 	responsePipeline         chan *Response
 	readerClose, writerClose chan bool
 
+	// ctx is cancelled when the connection is closed, see HandlerBag.ConnCtx
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	userMetaInfo *entities.PlayerMetaData
 
 	requestMu sync.Mutex
@@ -37,6 +41,7 @@ type Connection struct {
 
 func (conn *Connection) close(wg *sync.WaitGroup) {
 	conn.closeOnce.Do(func() {
+		conn.cancel()
 		close(conn.readerClose)
 		close(conn.writerClose)
 		conn.conn.Close()
@@ -77,23 +82,18 @@ func (conn *Connection) reader() {
 
 func (conn *Connection) writer() {
 	ticker := time.NewTicker(pingPeriod)
+	// the response pipeline is not closed, the handlers which are still running would panic on it,
+	// they stop sending when the context of the connection is cancelled
 	defer func() {
-		close(conn.responsePipeline)
 		ticker.Stop()
 		conn.askForRemoving()
 	}()
 
 	for {
 		select {
-		case resp, ok := <-conn.responsePipeline:
+		case resp := <-conn.responsePipeline:
 			conn.conn.SetWriteDeadline(time.Now().Add(writeWait))
 
-			if !ok {
-				conn.conn.WriteMessage(websocket.CloseMessage, []byte{})
-
-				return
-			}
-
 			conn.write(resp)
 		case <-ticker.C:
 			conn.conn.SetWriteDeadline(time.Now().Add(writeWait))
@@ -116,7 +116,7 @@ func (conn *Connection) write(resp *Response) {
 func (conn *Connection) read(message []byte) {
 	req := Request{}
 	if err := json.Unmarshal(message, &req); err != nil {
-		conn.responsePipeline <- BadRequest(err)
+		conn.send(BadRequest(err))
 
 		return
 	}
@@ -124,6 +124,13 @@ func (conn *Connection) read(message []byte) {
 	conn.submitRequest(req)
 }
 
+func (conn *Connection) send(resp *Response) {
+	select {
+	case conn.responsePipeline <- resp:
+	case <-conn.ctx.Done():
+	}
+}
+
 func (conn *Connection) pongHandler(str string) error {
 	return conn.conn.SetReadDeadline(time.Now().Add(pongWait))
 }
@@ -134,14 +141,14 @@ func (conn *Connection) submitRequest(req Request) {
 	conn.requestMu.Unlock()
 
 	if !ok {
-		conn.responsePipeline <- NotFound(req.UUID)
+		conn.send(NotFound(req.UUID))
 
 		return
 	}
 
 	requestBody, err := json.Marshal(req.Payload)
 	if err != nil {
-		conn.responsePipeline <- BadRequest(err)
+		conn.send(BadRequest(err))
 
 		return
 	}
@@ -152,6 +159,7 @@ func (conn *Connection) submitRequest(req Request) {
 	hf(HandlerBag{
 		Payload:          req.Payload,
 		Ctx:              ctx,
+		ConnCtx:          conn.ctx,
 		PlayerMetaData:   conn.userMetaInfo.CopyAndSetRequest(requestBody),
 		ResponsePipeline: conn.responsePipeline,
 		UUID:             req.UUID})
@@ -12,13 +12,24 @@ type Handler interface {
 }
 
 type HandlerBag struct {
-	Payload          interface{}
-	Ctx              context.Context
+	Payload interface{}
+	// Ctx is not cancelled with the connection, so the bets and the history records in flight are finished.
+	Ctx context.Context
+	// ConnCtx is cancelled when the connection is closed, the long-running handlers (autoplay) stop on it.
+	ConnCtx          context.Context
 	UUID             uuid.UUID
 	PlayerMetaData   *entities.PlayerMetaData
 	ResponsePipeline chan *Response
 }
 
+// Send passes the response to the writer of the connection, the response is dropped if the connection is closed.
+func (bag HandlerBag) Send(resp *Response) {
+	select {
+	case bag.ResponsePipeline <- resp:
+	case <-bag.ConnCtx.Done():
+	}
+}
+
 type HandleFunc func(HandlerBag)
 
 type Router struct {
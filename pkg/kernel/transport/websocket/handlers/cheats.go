@@ -28,10 +28,10 @@ func (h *cheatsHandler) Shutdown() {}
 
 func (h *cheatsHandler) cheats(bag websocket.HandlerBag) {
 	if err := h.facade.AddCheat(context.Background(), bag.Payload); err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OKNoContent(bag.UUID)
+	bag.Send(websocket.OKNoContent(bag.UUID))
 }
@@ -3,6 +3,7 @@ package handlers
 const (
 	ActionState                            = "core/state"
 	ActionWager                            = "core/wager"
+	ActionAutoplay                         = "core/autoplay"
 	ActionGambleAnyWin                     = "core/gamble_any_win"
 	ActionKeepGenerating                   = "core/keep_generating"
 	ActionSpinsHistory                     = "core/spins_history"
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/facade"
 	"bitbucket.org/play-workspace/base-slot-server/pkg/kernel/transport/websocket"
 )
@@ -18,6 +20,7 @@ func (h *gameFlowHandler) Shutdown() {}
 func (h *gameFlowHandler) Register(r *websocket.Router) {
 	r.Accept(ActionState, h.state)
 	r.Accept(ActionWager, h.wager)
+	r.Accept(ActionAutoplay, h.autoplay)
 	r.Accept(ActionGambleAnyWin, h.gambleAnyWin)
 	r.Accept(ActionKeepGenerating, h.keepGenerating)
 	r.Accept(ActionSpinsHistory, h.spinsHistory)
@@ -31,106 +34,127 @@ func (h *gameFlowHandler) Register(r *websocket.Router) {
 func (h *gameFlowHandler) state(bag websocket.HandlerBag) {
 	gameState, err := h.facade.InitState(bag.Ctx, bag.Payload)
 	if err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(gameState, bag.UUID)
+	bag.Send(websocket.OK(gameState, bag.UUID))
 }
 
 func (h *gameFlowHandler) wager(bag websocket.HandlerBag) {
 	gameState, err := h.facade.Wager(bag.Ctx, bag.Payload, bag.PlayerMetaData)
 	if err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
+
+		return
+	}
+
+	bag.Send(websocket.OK(gameState, bag.UUID))
+}
+
+// autoplay streams the rounds as partial responses and finishes with the summary.
+// The autoplay stops when the connection is closed, the round in flight is finished.
+func (h *gameFlowHandler) autoplay(bag websocket.HandlerBag) {
+	ctx, cancel := context.WithCancel(bag.Ctx)
+	defer cancel()
+	defer context.AfterFunc(bag.ConnCtx, cancel)()
+
+	resp, err := h.facade.Autoplay(ctx, bag.Payload, bag.PlayerMetaData, func(round *facade.AutoplayRound) error {
+		bag.Send(websocket.Partial(round, bag.UUID))
+
+		return bag.ConnCtx.Err()
+	})
+	if err != nil {
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(gameState, bag.UUID)
+	bag.Send(websocket.OK(resp, bag.UUID))
 }
 
 func (h *gameFlowHandler) gambleAnyWin(bag websocket.HandlerBag) {
 	gameState, err := h.facade.GambleAnyWin(bag.Ctx, bag.Payload, bag.PlayerMetaData)
 	if err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(gameState, bag.UUID)
+	bag.Send(websocket.OK(gameState, bag.UUID))
 }
 
 func (h *gameFlowHandler) keepGenerating(bag websocket.HandlerBag) {
 	gameState, err := h.facade.KeepGenerating(bag.Ctx, bag.Payload, bag.PlayerMetaData)
 	if err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(gameState, bag.UUID)
+	bag.Send(websocket.OK(gameState, bag.UUID))
 }
 
 func (h *gameFlowHandler) getFreeSpins(bag websocket.HandlerBag) {
 	freeSpins, err := h.facade.FreeSpins(bag.Ctx, bag.Payload)
 	if err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(freeSpins, bag.UUID)
+	bag.Send(websocket.OK(freeSpins, bag.UUID))
 }
 
 func (h *gameFlowHandler) cancelFreeSpins(bag websocket.HandlerBag) {
 	if err := h.facade.CancelSpins(bag.Ctx, bag.Payload); err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OKNoContent(bag.UUID)
+	bag.Send(websocket.OKNoContent(bag.UUID))
 }
 
 func (h *gameFlowHandler) getFreeSpinsWithIntegratorBet(bag websocket.HandlerBag) {
 	freeSpins, err := h.facade.FreeSpinsWithIntegratorBet(bag.Ctx, bag.Payload)
 	if err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(freeSpins, bag.UUID)
+	bag.Send(websocket.OK(freeSpins, bag.UUID))
 }
 
 func (h *gameFlowHandler) cancelFreeSpinsWithIntegratorBet(bag websocket.HandlerBag) {
 	if err := h.facade.CancelSpinsWithIntegratorBet(bag.Ctx, bag.Payload); err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OKNoContent(bag.UUID)
+	bag.Send(websocket.OKNoContent(bag.UUID))
 }
 
 func (h *gameFlowHandler) spinsHistory(bag websocket.HandlerBag) {
 	pagination, err := h.facade.Paginate(bag.Ctx, bag.Payload)
 	if err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(pagination, bag.UUID)
+	bag.Send(websocket.OK(pagination, bag.UUID))
 }
 
 func (h *gameFlowHandler) updateSpinIndexes(bag websocket.HandlerBag) {
 	if err := h.facade.UpdateSpinIndexes(bag.Ctx, bag.Payload, bag.PlayerMetaData); err != nil {
-		handleServiceError(bag.ResponsePipeline, err, bag.UUID)
+		handleServiceError(bag, err)
 
 		return
 	}
 
-	bag.ResponsePipeline <- websocket.OK(nil, bag.UUID)
+	bag.Send(websocket.OK(nil, bag.UUID))
 }
@@ -14,22 +14,37 @@ var errorMap = map[error]func(data interface{}, uuid uuid.UUID) *websocket.Respo
 	errs.ErrWrongFreeSpinID:       websocket.Conflict,
 	errs.ErrLastSpinWasNotShown:   websocket.Conflict,
 	errs.ErrNotEnoughMoney:        websocket.PaymentRequired,
+	errs.ErrIdempotencyKeyReused:  websocket.Conflict,
+	errs.ErrRequestInProgress:     websocket.Conflict,
+
+	errs.ErrCanNotGamble:        websocket.Conflict,
+	errs.ErrGambleLost:          websocket.Conflict,
+	errs.ErrGambleLimitReached:  websocket.Conflict,
+	errs.ErrGambleMaxWinReached: websocket.Conflict,
+	errs.ErrNoWinToGamble:       websocket.Conflict,
+
+	errs.ErrStakeLimitExceeded:      websocket.Forbidden,
+	errs.ErrSessionLossLimitReached: websocket.Forbidden,
+	errs.ErrSpinTooFast:             websocket.Forbidden,
+	errs.ErrAutoplayNotAllowed:      websocket.Forbidden,
+	errs.ErrAutoplayIsNotSupported:  websocket.Forbidden,
+	errs.ErrTurboNotAllowed:         websocket.Forbidden,
 }
 
-func handleServiceError(broadcaster chan *websocket.Response, err error, requestUUID uuid.UUID) {
+func handleServiceError(bag websocket.HandlerBag, err error) {
 	internalValidationError, ok := err.(errs.InternalValidationError)
 	if ok {
-		broadcaster <- websocket.ValidationFailed(internalValidationError.Err)
+		bag.Send(websocket.ValidationFailed(internalValidationError.Err))
 
 		return
 	}
 
 	fn, ok := errorMap[err]
 	if !ok {
-		broadcaster <- websocket.ServerError(err, requestUUID)
+		bag.Send(websocket.ServerError(err, bag.UUID))
 
 		return
 	}
 
-	broadcaster <- fn(err, requestUUID)
+	bag.Send(fn(err, bag.UUID))
 }
@@ -39,6 +39,13 @@ func OK(data interface{}, uuid uuid.UUID) *Response {
 	return new(StatusSuccess, meta, data)
 }
 
+// Partial is the successful response which is followed by the other responses with the same uuid.
+func Partial(data interface{}, uuid uuid.UUID) *Response {
+	meta := map[string]interface{}{"uuid": uuid, "partial": true}
+
+	return new(StatusSuccess, meta, data)
+}
+
 func OKNoContent(uuid uuid.UUID) *Response {
 	meta := map[string]interface{}{"uuid": uuid}
 
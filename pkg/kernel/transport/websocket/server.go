@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"context"
 	"net/http"
 	"sync"
 
@@ -71,10 +72,15 @@ func (s *Server) ServeWS(ctx *gin.Context) error {
 		return err
 	}
 
+	connCtx, cancel := context.WithCancel(context.Background())
+
 	realConn := &Connection{
 		conn:   conn,
 		facade: s.facade,
 
+		ctx:    connCtx,
+		cancel: cancel,
+
 		responsePipeline: make(chan *Response),
 		router:           s.router,
 
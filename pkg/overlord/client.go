@@ -15,6 +15,8 @@ type Client interface {
 	InitUserState(ctx context.Context, game, integrator string, lordParams interface{}) (state *InitUserStateOut, err error)
 	GetStateBySessionToken(ctx context.Context, token string) (*InitUserStateOut, error)
 	AtomicBet(ctx context.Context, sessionToken, freeBetID, roundID string, wager, award int64, isGamble bool) (*AtomicBetOut, error)
+	// RollbackBet cancels the placed bet, it is the compensation of the round which can not be finished.
+	RollbackBet(ctx context.Context, sessionToken, transactionID string) (*RollbackOut, error)
 
 	GetAvailableFreeSpins(ctx context.Context, sessionToken string) (*GetAvailableFreeBetsOut, error)
 	CancelAvailableFreeSpins(ctx context.Context, sessionToken string) error
@@ -286,6 +288,17 @@ func (o *client) AtomicBet(ctx context.Context, sessionToken, freeBetID, roundID
 	return
 }
 
+func (o *client) RollbackBet(ctx context.Context, _, transactionID string) (*RollbackOut, error) {
+	out, err := o.api.RollbackBet(ctx, &RollbackIn{TransactionId: transactionID})
+	if err != nil {
+		zap.S().Errorf("rollback bet error: %s, transaction: %v", err.Error(), transactionID)
+
+		return nil, mapError(err)
+	}
+
+	return out, nil
+}
+
 func (o *client) SaveDefaultWagerInFreeBetValue(ctx context.Context, sessionToken string, freeBetID string, value int64) error {
 	zap.S().Info("repo: SaveDefaultWagerInFreeBetValue starting...")
 
@@ -74,3 +74,23 @@ func mapError(err error) error {
 
 	return nil
 }
+
+// IsDeclined reports whether the bet was refused by overlord, so the wallet is not changed by it.
+// Other errors (for example timeouts) leave the result of the bet unknown.
+func IsDeclined(err error) bool {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return true
+	}
+
+	for _, declined := range []error{
+		ErrBalanceTooLow, ErrWrongSessionToken, ErrWrongFreeSpinID,
+		ErrSessionTokenExpired, ErrUserHasDifferentCurrency, ErrUserIsBlocked,
+	} {
+		if errors.Is(err, declined) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package rng
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+)
+
+const golden = 0x9e3779b97f4a7c15
+
+var ErrZeroMax = errors.New("max must be positive")
+
+// SeededClient is a deterministic PRNG client for reproducible simulations, never use it for real rounds.
+type SeededClient struct {
+	pcg *rand.PCG
+	rnd *rand.Rand
+	mu  sync.Mutex
+}
+
+func NewSeededClient(seed uint64) *SeededClient {
+	pcg := rand.NewPCG(seed, seed^golden)
+
+	return &SeededClient{pcg: pcg, rnd: rand.New(pcg)}
+}
+
+// Seed restarts the client with the seed.
+func (c *SeededClient) Seed(seed uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pcg.Seed(seed, seed^golden)
+}
+
+func (c *SeededClient) Rand(max uint64) (uint64, error) {
+	if max == 0 {
+		return 0, ErrZeroMax
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.rnd.Uint64N(max), nil
+}
+
+func (c *SeededClient) RandSlice(maxSlice []uint64) ([]uint64, error) {
+	res := make([]uint64, 0, len(maxSlice))
+
+	for _, max := range maxSlice {
+		value, err := c.Rand(max)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, value)
+	}
+
+	return res, nil
+}
+
+func (c *SeededClient) RandFloat() (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.rnd.Float64(), nil
+}
+
+func (c *SeededClient) RandFloatSlice(count int) ([]float64, error) {
+	res := make([]float64, 0, count)
+
+	for i := 0; i < count; i++ {
+		value, _ := c.RandFloat()
+		res = append(res, value)
+	}
+
+	return res, nil
+}
+
+// DeriveSeed returns the seed of the independent stream (for example, a spin index) of the base seed.
+func DeriveSeed(seed, stream uint64) uint64 {
+	// splitmix64 finalizer
+	z := seed + (stream+1)*golden
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return z ^ (z >> 31)
+}
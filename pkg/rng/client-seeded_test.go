@@ -0,0 +1,27 @@
+package rng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeededClient_Reproducible(t *testing.T) {
+	c1, c2 := NewSeededClient(42), NewSeededClient(7)
+	c2.Seed(42)
+
+	for i := 0; i < 100; i++ {
+		v1, err := c1.Rand(1000)
+		require.NoError(t, err)
+
+		v2, err := c2.Rand(1000)
+		require.NoError(t, err)
+
+		require.Equal(t, v1, v2)
+	}
+
+	require.NotEqual(t, DeriveSeed(42, 0), DeriveSeed(42, 1))
+
+	_, err := c1.Rand(0)
+	require.ErrorIs(t, err, ErrZeroMax)
+}
@@ -0,0 +1,57 @@
+package rounds
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type memoryStorage struct {
+	rounds map[uuid.UUID]Round
+	mu     sync.Mutex
+}
+
+// NewMemoryStorage returns the storage for a single server, rounds are lost on restart.
+func NewMemoryStorage() Storage {
+	return &memoryStorage{rounds: map[uuid.UUID]Round{}}
+}
+
+func (m *memoryStorage) Save(_ context.Context, round *Round) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rounds[round.ID] = *round
+
+	return nil
+}
+
+func (m *memoryStorage) Delete(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.rounds, id)
+
+	return nil
+}
+
+func (m *memoryStorage) Unconfirmed(_ context.Context, userID uuid.UUID, game string) ([]*Round, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rounds []*Round
+
+	for _, round := range m.rounds {
+		if round.UserID == userID && round.Game == game {
+			cp := round
+			rounds = append(rounds, &cp)
+		}
+	}
+
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i].CreatedAt.Before(rounds[j].CreatedAt)
+	})
+
+	return rounds, nil
+}
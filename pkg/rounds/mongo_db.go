@@ -0,0 +1,75 @@
+package rounds
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const RoundsCollectionName = "pending_rounds"
+
+type mongoDBStorage struct {
+	coll   *mongo.Collection
+	client *mongo.Client
+}
+
+type MongoDBConfig struct {
+	URL  string
+	Name string
+}
+
+func NewMongoDBStorage(cfg *MongoDBConfig) (Storage, error) {
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(cfg.URL))
+	if err != nil {
+		zap.S().Error(err)
+		return nil, err
+	}
+
+	if err = client.Ping(context.TODO(), nil); err != nil {
+		return nil, err
+	}
+
+	coll := client.Database(cfg.Name).Collection(RoundsCollectionName)
+
+	_, err = coll.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "game", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoDBStorage{coll: coll, client: client}, nil
+}
+
+func (m *mongoDBStorage) Save(ctx context.Context, round *Round) error {
+	_, err := m.coll.ReplaceOne(ctx, bson.D{{Key: "_id", Value: round.ID}}, round, options.Replace().SetUpsert(true))
+
+	return err
+}
+
+func (m *mongoDBStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := m.coll.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+
+	return err
+}
+
+func (m *mongoDBStorage) Unconfirmed(ctx context.Context, userID uuid.UUID, game string) ([]*Round, error) {
+	cursor, err := m.coll.Find(ctx,
+		bson.D{{Key: "user_id", Value: userID}, {Key: "game", Value: game}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var rounds []*Round
+
+	if err = cursor.All(ctx, &rounds); err != nil {
+		return nil, err
+	}
+
+	return rounds, nil
+}
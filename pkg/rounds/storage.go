@@ -0,0 +1,50 @@
+package rounds
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	// StatusPending is saved before the bet, the round stays pending if the bet result is unknown.
+	StatusPending Status = "pending"
+	// StatusPaid is the placed bet which is not saved to the history yet.
+	StatusPaid Status = "paid"
+	// StatusRollback is the placed bet which is rolled back, it is kept until overlord confirms the rollback.
+	StatusRollback Status = "rollback"
+)
+
+// Round is the write-ahead record of the round, it is saved before the bet and deleted when the round
+// is saved to the history or the bet is rolled back or declined.
+type Round struct {
+	ID            uuid.UUID `bson:"_id"`
+	UserID        uuid.UUID `bson:"user_id"`
+	Game          string    `bson:"game"`
+	SessionToken  string    `bson:"session_token"`
+	Status        Status    `bson:"status"`
+	Wager         int64     `bson:"wager"`
+	Award         int64     `bson:"award"`
+	FreeSpinID    string    `bson:"free_spin_id,omitempty"`
+	TransactionID string    `bson:"transaction_id,omitempty"`
+	// Record is the marshaled history record, the paid round without it is rolled back.
+	Record []byte `bson:"record,omitempty"`
+	// NoHistory marks the round of the game without history, the paid round is deleted without the record.
+	NoHistory bool `bson:"no_history,omitempty"`
+	// Jackpots is the marshaled jackpot settlement of the round, it is rolled back with the bet.
+	Jackpots  []byte    `bson:"jackpots,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// Storage keeps the rounds until they are confirmed, so they survive the restart of the server.
+type Storage interface {
+	// Save inserts or replaces the round by the id.
+	Save(ctx context.Context, round *Round) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Unconfirmed returns the rounds of the user in the game from the oldest.
+	Unconfirmed(ctx context.Context, userID uuid.UUID, game string) ([]*Round, error)
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	XLSXReport = "xlsx"
+	CSVReport  = "csv"
+	JSONReport = "json"
+)
+
+// ReportWriter writes the report pages, base is the report path without the extension.
+type ReportWriter interface {
+	Write(base string, pages []Page) error
+}
+
+// NewReportWriter returns the writer of the format, empty format means xlsx.
+func NewReportWriter(format string) (ReportWriter, error) {
+	switch format {
+	case XLSXReport, "":
+		return xlsxWriter{}, nil
+	case CSVReport:
+		return csvWriter{}, nil
+	case JSONReport:
+		return jsonWriter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown report format %s", format)
+	}
+}
+
+type xlsxWriter struct{}
+
+func (xlsxWriter) Write(base string, pages []Page) error {
+	excel, err := ExportMultiPageXLSX(pages)
+	if err != nil {
+		return err
+	}
+
+	return excel.SaveAs(base + ".xlsx")
+}
+
+// csvWriter writes every page to its own file in the base directory.
+type csvWriter struct{}
+
+func (csvWriter) Write(base string, pages []Page) error {
+	if err := os.MkdirAll(base, os.ModePerm); err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		file, err := os.Create(filepath.Join(base, pageFileName(page.Name)+".csv"))
+		if err != nil {
+			return err
+		}
+
+		w := csv.NewWriter(file)
+		err = w.WriteAll(page.Table)
+
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type jsonWriter struct{}
+
+type jsonPage struct {
+	Name  string     `json:"name"`
+	Table [][]string `json:"table"`
+}
+
+func (jsonWriter) Write(base string, pages []Page) error {
+	jsonPages := make([]jsonPage, 0, len(pages))
+
+	for _, page := range pages {
+		jsonPages = append(jsonPages, jsonPage{Name: page.Name, Table: page.Table})
+	}
+
+	b, err := json.MarshalIndent(jsonPages, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(base+".json", b, 0o644)
+}
+
+// pageFileName is the page name in snake case: "RTP Convergence" is "rtp_convergence".
+func pageFileName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "_"))
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testPages = []Page{{
+	Name:  "Report",
+	Table: [][]string{{"Game", "roulette"}, {"RTP", "0.96"}},
+}, {
+	Name:  "Win Distribution",
+	Table: [][]string{{"From", "To"}, {"0", "1"}},
+}}
+
+func TestReportWriters(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("csv", func(t *testing.T) {
+		writer, err := NewReportWriter(CSVReport)
+		require.NoError(t, err)
+
+		base := filepath.Join(dir, "csv")
+		require.NoError(t, writer.Write(base, testPages))
+
+		b, err := os.ReadFile(filepath.Join(base, "win_distribution.csv"))
+		require.NoError(t, err)
+		require.Equal(t, "From,To\n0,1\n", string(b))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		writer, err := NewReportWriter(JSONReport)
+		require.NoError(t, err)
+
+		base := filepath.Join(dir, "json")
+		require.NoError(t, writer.Write(base, testPages))
+
+		b, err := os.ReadFile(base + ".json")
+		require.NoError(t, err)
+
+		var pages []Page
+		require.NoError(t, json.Unmarshal(b, &pages))
+		require.Equal(t, testPages, pages)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := NewReportWriter("pdf")
+		require.Error(t, err)
+	})
+}